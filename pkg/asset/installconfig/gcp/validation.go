@@ -550,33 +550,41 @@ func validateZones(client API, ic *types.InstallConfig) field.ErrorList {
 	}
 
 	projZones := sets.New[string]()
+	downZones := sets.New[string]()
 	for _, zone := range zones {
 		projZones.Insert(zone.Name)
+		if zone.Status == "DOWN" {
+			downZones.Insert(zone.Name)
+		}
+	}
+	if downZones.Equal(projZones) {
+		return append(allErrs, field.Invalid(field.NewPath("platform", "gcp", "region"), ic.GCP.Region, "no zones are up in region"))
 	}
 
-	const errMsg = "zone(s) not found in region"
+	validatePoolZones := func(fldPath *field.Path, zones []string) {
+		const (
+			notFoundMsg = "zone(s) not found in region"
+			downMsg     = "zone(s) are down"
+		)
+		if diff := sets.New(zones...).Difference(projZones); len(diff) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, sets.List(diff), notFoundMsg))
+		}
+		if down := sets.New(zones...).Intersection(downZones); len(down) > 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, sets.List(down), downMsg))
+		}
+	}
 
 	if ic.Platform.GCP.DefaultMachinePlatform != nil {
-		diff := sets.New(ic.Platform.GCP.DefaultMachinePlatform.Zones...).Difference(projZones)
-		if len(diff) > 0 {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("platform", "gcp", "defaultMachinePlatform", "zones"), sets.List(diff), errMsg))
-		}
+		validatePoolZones(field.NewPath("platform", "gcp", "defaultMachinePlatform", "zones"), ic.Platform.GCP.DefaultMachinePlatform.Zones)
 	}
 
 	if ic.ControlPlane != nil && ic.ControlPlane.Platform.GCP != nil {
-		diff := sets.New(ic.ControlPlane.Platform.GCP.Zones...).Difference(projZones)
-		if len(diff) > 0 {
-			allErrs = append(allErrs, field.Invalid(field.NewPath("controlPlane", "platform", "gcp", "zones"), sets.List(diff), errMsg))
-		}
+		validatePoolZones(field.NewPath("controlPlane", "platform", "gcp", "zones"), ic.ControlPlane.Platform.GCP.Zones)
 	}
 
 	for idx, compute := range ic.Compute {
-		fldPath := field.NewPath("compute").Index(idx)
 		if compute.Platform.GCP != nil {
-			diff := sets.New(compute.Platform.GCP.Zones...).Difference(projZones)
-			if len(diff) > 0 {
-				allErrs = append(allErrs, field.Invalid(fldPath.Child("platform", "gcp", "zones"), sets.List(diff), errMsg))
-			}
+			validatePoolZones(field.NewPath("compute").Index(idx).Child("platform", "gcp", "zones"), compute.Platform.GCP.Zones)
 		}
 	}
 
@@ -746,6 +746,48 @@ func TestValidateZones(t *testing.T) {
 	}
 }
 
+func TestValidateZonesDown(t *testing.T) {
+	cases := []struct {
+		name           string
+		zones          []*compute.Zone
+		expectedErrMsg string
+	}{
+		{
+			name:  "Zones up",
+			zones: []*compute.Zone{{Name: "us-central1-a", Status: "UP"}, {Name: "us-central1-b", Status: "UP"}},
+		},
+		{
+			name:           "Configured zone down",
+			zones:          []*compute.Zone{{Name: "us-central1-a", Status: "UP"}, {Name: "us-central1-b", Status: "DOWN"}},
+			expectedErrMsg: `^\[platform.gcp.defaultMachinePlatform.zones: Invalid value: \[\]string\{"us\-central1\-b"\}: zone\(s\) are down\]$`,
+		},
+		{
+			name:           "All zones down",
+			zones:          []*compute.Zone{{Name: "us-central1-a", Status: "DOWN"}, {Name: "us-central1-b", Status: "DOWN"}},
+			expectedErrMsg: `^\[platform.gcp.region: Invalid value: "us\-east1": no zones are up in region\]$`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			gcpClient := mock.NewMockAPI(mockCtrl)
+			gcpClient.EXPECT().GetZones(gomock.Any(), gomock.Any(), gomock.Any()).Return(tc.zones, nil).AnyTimes()
+
+			ic := validInstallConfig()
+			ic.Platform.GCP.DefaultMachinePlatform.Zones = []string{"us-central1-a", "us-central1-b"}
+
+			errs := validateZones(gcpClient, ic)
+			if tc.expectedErrMsg != "" {
+				assert.Regexp(t, tc.expectedErrMsg, errs)
+			} else {
+				assert.Empty(t, errs)
+			}
+		})
+	}
+}
+
 func TestValidateInstanceType(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -3,6 +3,8 @@ package openstack
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/gophercloud/utils/v2/openstack/clientconfig"
@@ -35,6 +37,60 @@ func GetSession(cloudName string) (*Session, error) {
 	}, nil
 }
 
+// GetSessionFromCloudsYAML returns an OpenStack session for a given cloud name
+// in the clouds.yaml content, e.g. read from a Secret, instead of the clouds.yaml
+// files on disk.
+func GetSessionFromCloudsYAML(cloudName string, cloudsYAML []byte) (*Session, error) {
+	opts := openstackdefaults.DefaultClientOpts(cloudName)
+	opts.YAMLOpts = &contentLoadOpts{content: cloudsYAML}
+
+	cloudConfig, err := clientconfig.GetCloudFromYAML(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		CloudConfig: cloudConfig,
+		ClientOpts:  opts,
+	}, nil
+}
+
+// SelectCloud returns the name of the cloud to use from the clouds.yaml files
+// on disk. It is the given name when set, and the only cloud of clouds.yaml
+// otherwise. It fails when no cloud is named and clouds.yaml has several.
+func SelectCloud(cloudName string) (string, error) {
+	return selectCloud(cloudName, new(yamlLoadOpts))
+}
+
+// SelectCloudFromCloudsYAML returns the name of the cloud to use from the
+// clouds.yaml content, like SelectCloud.
+func SelectCloudFromCloudsYAML(cloudName string, cloudsYAML []byte) (string, error) {
+	return selectCloud(cloudName, &contentLoadOpts{content: cloudsYAML})
+}
+
+// selectCloud picks the only cloud of clouds.yaml when no cloud is named, so
+// that the name of the cloud in use is always known. Failing to load
+// clouds.yaml is left to the loading of the cloud.
+func selectCloud(cloudName string, yamlOpts clientconfig.YAMLOptsBuilder) (string, error) {
+	if cloudName != "" {
+		return cloudName, nil
+	}
+	// A clouds.yaml which fails to load is reported when loading the cloud.
+	clouds, _ := yamlOpts.LoadCloudsYAML()
+	if len(clouds) == 0 {
+		return "", nil
+	}
+	names := make([]string, 0, len(clouds))
+	for name := range clouds {
+		names = append(names, name)
+	}
+	if len(names) > 1 {
+		sort.Strings(names)
+		return "", fmt.Errorf("clouds.yaml has several clouds (%s), set the name of the cloud to use in platform.openstack.cloud", strings.Join(names, ", "))
+	}
+	logrus.Infof("Using the cloud %q, the only cloud of clouds.yaml, since platform.openstack.cloud is not set", names[0])
+	return names[0], nil
+}
+
 type yamlLoadOpts struct{}
 
 func (opts yamlLoadOpts) LoadCloudsYAML() (map[string]clientconfig.Cloud, error) {
@@ -98,3 +154,25 @@ func loadAndLog(fn func() (string, []byte, error)) ([]byte, error) {
 
 	return content, nil
 }
+
+// contentLoadOpts loads the clouds from the clouds.yaml content, without a
+// secure.yaml or clouds-public.yaml.
+type contentLoadOpts struct {
+	content []byte
+}
+
+func (opts contentLoadOpts) LoadCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	var clouds clientconfig.Clouds
+	if err := yaml.Unmarshal(opts.content, &clouds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml: %w", err)
+	}
+	return clouds.Clouds, nil
+}
+
+func (opts contentLoadOpts) LoadSecureCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	return nil, nil
+}
+
+func (opts contentLoadOpts) LoadPublicCloudsYAML() (map[string]clientconfig.Cloud, error) {
+	return nil, nil
+}
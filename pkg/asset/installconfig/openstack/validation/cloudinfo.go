@@ -37,6 +37,7 @@ import (
 type CloudInfo struct {
 	APIFIP                  *floatingips.FloatingIP
 	ExternalNetwork         *networks.Network
+	FloatingNetworks        map[string]*networks.Network
 	Flavors                 map[string]Flavor
 	IngressFIP              *floatingips.FloatingIP
 	ControlPlanePortSubnets []*subnets.Subnet
@@ -79,8 +80,9 @@ func GetCloudInfo(ctx context.Context, ic *types.InstallConfig) (*CloudInfo, err
 	}
 
 	ci = &CloudInfo{
-		clients: &clients{},
-		Flavors: map[string]Flavor{},
+		clients:          &clients{},
+		Flavors:          map[string]Flavor{},
+		FloatingNetworks: map[string]*networks.Network{},
 	}
 
 	opts := openstackdefaults.DefaultClientOpts(ic.OpenStack.Cloud)
@@ -131,6 +133,19 @@ func (ci *CloudInfo) collectInfo(ctx context.Context, ic *types.InstallConfig) e
 		return fmt.Errorf("failed to fetch external network info: %w", err)
 	}
 
+	for _, class := range ic.OpenStack.LoadBalancerClasses {
+		if class.FloatingNetwork == "" {
+			continue
+		}
+		if _, seen := ci.FloatingNetworks[class.FloatingNetwork]; seen {
+			continue
+		}
+		ci.FloatingNetworks[class.FloatingNetwork], err = ci.getNetworkByName(ctx, class.FloatingNetwork)
+		if err != nil {
+			return fmt.Errorf("failed to fetch floating network info: %w", err)
+		}
+	}
+
 	// Fetch the image info if the user provided a Glance image name
 	imagePtr := ic.OpenStack.ClusterOSImage
 	if imagePtr != "" {
@@ -28,6 +28,9 @@ func ValidatePlatform(p *openstack.Platform, n *types.Networking, ci *CloudInfo)
 	// validate the externalNetwork
 	allErrs = append(allErrs, validateExternalNetwork(p, ci, fldPath)...)
 
+	// validate the floating networks of the load balancer classes
+	allErrs = append(allErrs, validateLoadBalancerClasses(p, ci, fldPath)...)
+
 	// validate floating ips
 	allErrs = append(allErrs, validateFloatingIPs(p, ci, fldPath)...)
 
@@ -122,6 +125,16 @@ func validateExternalNetwork(p *openstack.Platform, ci *CloudInfo, fldPath *fiel
 	return allErrs
 }
 
+// validateLoadBalancerClasses validates the user's input for the floating networks of the load balancer classes and returns a list of all validation errors
+func validateLoadBalancerClasses(p *openstack.Platform, ci *CloudInfo, fldPath *field.Path) (allErrs field.ErrorList) {
+	for i, class := range p.LoadBalancerClasses {
+		if class.FloatingNetwork != "" && ci.FloatingNetworks[class.FloatingNetwork] == nil {
+			allErrs = append(allErrs, field.NotFound(fldPath.Child("loadBalancerClasses").Index(i).Child("floatingNetwork"), class.FloatingNetwork))
+		}
+	}
+	return allErrs
+}
+
 func validateFloatingIPs(p *openstack.Platform, ci *CloudInfo, fldPath *field.Path) (allErrs field.ErrorList) {
 	if p.APIFloatingIP != "" {
 		if ci.APIFIP == nil {
@@ -225,6 +225,33 @@ func TestOpenStackPlatformValidation(t *testing.T) {
 			expectedError:  true,
 			expectedErrMsg: "platform.openstack.externalNetwork: Not found: \"valid-external-network\"",
 		},
+		{
+			name: "valid load balancer class floating network",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.LoadBalancerClasses = []openstack.LoadBalancerClass{{Name: "public", FloatingNetwork: "public-network"}}
+				return p
+			}(),
+			cloudInfo: func() *CloudInfo {
+				ci := validPlatformCloudInfo()
+				ci.FloatingNetworks = map[string]*networks.Network{"public-network": {ID: "public-network-id", Name: "public-network"}}
+				return ci
+			}(),
+			networking:    validNetworking(),
+			expectedError: false,
+		},
+		{
+			name: "load balancer class floating network not found",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.LoadBalancerClasses = []openstack.LoadBalancerClass{{Name: "public", FloatingNetwork: "public-network"}}
+				return p
+			}(),
+			cloudInfo:      validPlatformCloudInfo(),
+			networking:     validNetworking(),
+			expectedError:  true,
+			expectedErrMsg: `platform.openstack.loadBalancerClasses\[0\].floatingNetwork: Not found: "public-network"`,
+		},
 		{
 			name: "APIVIP inside subnet allocation pool",
 			platform: func() *openstack.Platform {
@@ -53,6 +53,8 @@ type validationContext struct {
 	TagManager          TagManager
 	regionTagCategoryID string
 	zoneTagCategoryID   string
+	regionTagCategory   string
+	zoneTagCategory     string
 	rhcosStream         *stream.Stream
 }
 
@@ -79,10 +81,12 @@ func getVCenterClient(failureDomain vsphere.FailureDomain, ic *types.InstallConf
 			}
 
 			validationCtx := validationContext{
-				TagManager:  vapitags.NewManager(vim25RestClient),
-				AuthManager: newAuthManager(vim25Client),
-				Finder:      find.NewFinder(vim25Client),
-				Client:      vim25Client,
+				TagManager:        vapitags.NewManager(vim25RestClient),
+				AuthManager:       newAuthManager(vim25Client),
+				Finder:            find.NewFinder(vim25Client),
+				Client:            vim25Client,
+				regionTagCategory: ic.VSphere.RegionTagCategoryName(),
+				zoneTagCategory:   ic.VSphere.ZoneTagCategoryName(),
 			}
 			return &validationCtx, cleanup, err
 		}
@@ -548,13 +552,14 @@ func validateTagCategories(validationCtx *validationContext) (string, string, er
 		return "", "", err
 	}
 
+	regionTagCategory, zoneTagCategory := validationCtx.tagCategories()
 	regionTagCategoryID := ""
 	zoneTagCategoryID := ""
 	for _, category := range categories {
 		switch category.Name {
-		case vsphere.TagCategoryRegion:
+		case regionTagCategory:
 			regionTagCategoryID = category.ID
-		case vsphere.TagCategoryZone:
+		case zoneTagCategory:
 			zoneTagCategoryID = category.ID
 		}
 		if len(zoneTagCategoryID) > 0 && len(regionTagCategoryID) > 0 {
@@ -562,11 +567,24 @@ func validateTagCategories(validationCtx *validationContext) (string, string, er
 		}
 	}
 	if len(zoneTagCategoryID) == 0 || len(regionTagCategoryID) == 0 {
-		return "", "", errors.New("tag categories openshift-zone and openshift-region must be created")
+		return "", "", fmt.Errorf("tag categories %s and %s must be created", zoneTagCategory, regionTagCategory)
 	}
 	return regionTagCategoryID, zoneTagCategoryID, nil
 }
 
+// tagCategories returns the names of the tag categories of the regions and
+// zones, which default to openshift-region and openshift-zone.
+func (v *validationContext) tagCategories() (string, string) {
+	region, zone := v.regionTagCategory, v.zoneTagCategory
+	if region == "" {
+		region = vsphere.TagCategoryRegion
+	}
+	if zone == "" {
+		zone = vsphere.TagCategoryZone
+	}
+	return region, zone
+}
+
 func validateTagAttachment(validationCtx *validationContext, reference vim25types.ManagedObjectReference) error {
 	if validationCtx.TagManager == nil {
 		return nil
@@ -612,12 +630,13 @@ func validateTagAttachment(validationCtx *validationContext, reference vim25type
 			}
 		}
 	}
+	regionTagCategory, zoneTagCategory := validationCtx.tagCategories()
 	var errs []string
 	if !regionTagAttached {
-		errs = append(errs, fmt.Sprintf("tag associated with tag category %s not attached to this resource or ancestor", vsphere.TagCategoryRegion))
+		errs = append(errs, fmt.Sprintf("tag associated with tag category %s not attached to this resource or ancestor", regionTagCategory))
 	}
 	if !zoneTagAttached {
-		errs = append(errs, fmt.Sprintf("tag associated with tag category %s not attached to this resource or ancestor", vsphere.TagCategoryZone))
+		errs = append(errs, fmt.Sprintf("tag associated with tag category %s not attached to this resource or ancestor", zoneTagCategory))
 	}
 	return errors.New(strings.Join(errs, ","))
 }
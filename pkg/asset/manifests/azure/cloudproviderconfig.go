@@ -3,24 +3,63 @@ package azure
 import (
 	"bytes"
 	"encoding/json"
+	"sort"
+	"strings"
 
+	azureenv "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/azure"
+	azurevalidation "github.com/openshift/installer/pkg/types/azure/validation"
 )
 
 // CloudProviderConfig is the azure cloud provider config
 type CloudProviderConfig struct {
-	CloudName                azure.CloudEnvironment
-	TenantID                 string
-	SubscriptionID           string
-	ResourceGroupName        string
-	GroupLocation            string
-	ResourcePrefix           string
-	NetworkResourceGroupName string
-	NetworkSecurityGroupName string
-	VirtualNetworkName       string
-	SubnetName               string
-	ResourceManagerEndpoint  string
-	ARO                      bool
+	CloudName                  azure.CloudEnvironment
+	TenantID                   string
+	SubscriptionID             string
+	ResourceGroupName          string
+	GroupLocation              string
+	ResourcePrefix             string
+	NetworkResourceGroupName   string
+	LoadBalancerResourceGroup  string
+	LoadBalancerSku            string
+	OutboundType               azure.OutboundType
+	NetworkSecurityGroupName   string
+	VirtualNetworkName         string
+	SubnetName                 string
+	RouteTableName             string
+	ResourceManagerEndpoint    string
+	ActiveDirectoryEndpoint    string
+	GraphEndpoint              string
+	GalleryEndpoint            string
+	ARO                        bool
+	PutVMSSVMBatchSize         int
+	VMType                     string
+	PrimaryAvailabilitySetName string
+	Tags                       map[string]string
+	ServiceEndpoints           []azure.ServiceEndpoint
+	// ClusterName is the name the cloud provider is run with, the infrastructure
+	// ID, which names the load balancer of the cluster.
+	ClusterName string
+	// LoadBalancers are the additional standard load balancers. When set, the
+	// config lists the load balancer of the cluster followed by them in
+	// multipleStandardLoadBalancerConfigurations, e.g.
+	//
+	//	"loadBalancerBackendPoolConfigurationType": "nodeIP",
+	//	"multipleStandardLoadBalancerConfigurations": [
+	//		{"name": "<cluster name>", ...},
+	//		{"name": "<name>", "primaryVMSet": "<set>", "serviceLabelSelector": {"matchLabels": {...}}, ...}
+	//	]
+	//
+	// and the cloud provider joins the node IPs to their backend pools.
+	LoadBalancers []azure.LoadBalancer
+	// Minimal omits the fields which hold the defaults of the cloud provider.
+	Minimal bool
 }
 
 // JSON generates the cloud provider json config for the azure platform.
@@ -49,19 +88,30 @@ func (params CloudProviderConfig) JSON() (string, error) {
 		SecurityGroupName: params.NetworkSecurityGroupName,
 		VnetName:          params.VirtualNetworkName,
 		VnetResourceGroup: params.NetworkResourceGroupName,
-		RouteTableName:    params.ResourcePrefix + "-node-routetable",
+		// When empty, the cloud provider creates the load balancers in the cluster resource group.
+		LoadBalancerResourceGroup: params.LoadBalancerResourceGroup,
+		RouteTableName:            params.routeTableName(),
 		// client side rate limiting is problematic for scaling operations. We disable it by default.
 		// https://github.com/kubernetes-sigs/cloud-provider-azure/issues/247
 		// https://bugzilla.redhat.com/show_bug.cgi?id=1782516#c7
 		CloudProviderBackoff:         true,
 		CloudProviderBackoffDuration: 6,
 		VMType:                       "standard",
+		PrimaryAvailabilitySetName:   params.PrimaryAvailabilitySetName,
 
 		UseInstanceMetadata: true,
 		// default to standard load balancer, supports tcp resets on idle
 		// https://docs.microsoft.com/en-us/azure/load-balancer/load-balancer-tcp-reset
 		LoadBalancerSku:             "standard",
 		ExcludeMasterFromStandardLB: &excludeMasterFromStandardLB,
+		PutVMSSVMBatchSize:          params.PutVMSSVMBatchSize,
+	}
+
+	config.Tags, config.TagsMap = cloudProviderTags(params.Tags)
+
+	// The nodes are standard virtual machines unless specified otherwise.
+	if params.VMType != "" {
+		config.VMType = params.VMType
 	}
 
 	if params.ARO {
@@ -69,12 +119,71 @@ func (params CloudProviderConfig) JSON() (string, error) {
 	}
 
 	if params.CloudName == azure.StackCloud {
+		if err := azurevalidation.ValidateARMEndpoint(params.ResourceManagerEndpoint); err != nil {
+			return "", errors.Wrapf(err, "invalid ARM endpoint %q", params.ResourceManagerEndpoint)
+		}
 		config.authConfig.ResourceManagerEndpoint = params.ResourceManagerEndpoint
+		config.authConfig.ActiveDirectoryEndpoint = params.ActiveDirectoryEndpoint
+		config.authConfig.GraphEndpoint = params.GraphEndpoint
+		config.authConfig.GalleryEndpoint = params.GalleryEndpoint
 		config.authConfig.UseManagedIdentityExtension = false
 		config.LoadBalancerSku = "basic"
 		config.UseInstanceMetadata = false
 	}
 
+	// The cloud provider calls the overridden endpoints instead of the public
+	// ones of the environment, e.g. for private link with restricted egress.
+	if params.CloudName != azure.StackCloud {
+		for _, endpoint := range params.ServiceEndpoints {
+			switch endpoint.Name {
+			case azure.ResourceManagerServiceEndpoint:
+				config.authConfig.ResourceManagerEndpoint = endpoint.URL
+			case azure.ActiveDirectoryServiceEndpoint:
+				config.authConfig.ActiveDirectoryEndpoint = endpoint.URL
+			default:
+				return "", errors.Errorf("unsupported service endpoint %q, expected %q or %q", endpoint.Name, azure.ResourceManagerServiceEndpoint, azure.ActiveDirectoryServiceEndpoint)
+			}
+		}
+	}
+
+	if params.LoadBalancerSku != "" {
+		switch azure.LoadBalancerSKU(params.LoadBalancerSku) {
+		case azure.StandardLoadBalancerSKU, azure.BasicLoadBalancerSKU:
+			config.LoadBalancerSku = params.LoadBalancerSku
+		default:
+			return "", errors.Errorf("unsupported load balancer SKU %q, expected %q or %q", params.LoadBalancerSku, azure.StandardLoadBalancerSKU, azure.BasicLoadBalancerSKU)
+		}
+	}
+
+	if len(params.LoadBalancers) > 0 {
+		configs, err := multipleStandardLoadBalancerConfigurations(params.ClusterName, params.LoadBalancers)
+		if err != nil {
+			return "", err
+		}
+		if config.LoadBalancerSku != string(azure.StandardLoadBalancerSKU) {
+			return "", errors.Errorf("additional load balancers are not supported with the %s load balancer SKU", config.LoadBalancerSku)
+		}
+		// The cloud provider ignores the configurations of the load balancers
+		// unless the backend pools hold the IPs of the nodes.
+		config.LoadBalancerBackendPoolConfigurationType = "nodeIP"
+		config.MultipleStandardLoadBalancerConfigurations = configs
+	}
+
+	// When the egress goes through user-defined routes or a NAT gateway, the
+	// load balancer rules must not SNAT the outbound traffic of the nodes.
+	// This is only supported by standard load balancers.
+	switch params.OutboundType {
+	case azure.UserDefinedRoutingOutboundType, azure.NatGatewayOutboundType:
+		if config.LoadBalancerSku == string(azure.StandardLoadBalancerSKU) {
+			disableOutboundSNAT := true
+			config.DisableOutboundSNAT = &disableOutboundSNAT
+		}
+	}
+
+	if params.Minimal {
+		return minimalJSON(config)
+	}
+
 	buff := &bytes.Buffer{}
 	encoder := json.NewEncoder(buff)
 	encoder.SetIndent("", "\t")
@@ -83,3 +192,189 @@ func (params CloudProviderConfig) JSON() (string, error) {
 	}
 	return buff.String(), nil
 }
+
+// cloudProviderDefaults are the values the cloud provider defaults the fields
+// of the config to when unset, for the fields which are always emitted. The
+// load balancer SKU and VM type are not there, since the defaults of the cloud
+// provider differ from the ones of the installer.
+var cloudProviderDefaults = map[string]interface{}{
+	"tenantId":                             "",
+	"aadClientId":                          "",
+	"aadClientSecret":                      "",
+	"aadClientCertPath":                    "",
+	"aadClientCertPassword":                "",
+	"useManagedIdentityExtension":          false,
+	"userAssignedIdentityID":               "",
+	"subscriptionId":                       "",
+	"putVMSSVMBatchSize":                   float64(0),
+	"enableMigrateToIPBasedBackendPoolAPI": false,
+}
+
+// minimalJSON encodes the config without the fields holding the defaults of
+// the cloud provider, with the fields sorted by name.
+func minimalJSON(config config) (string, error) {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", err
+	}
+	for k, v := range cloudProviderDefaults {
+		if fields[k] == v {
+			delete(fields, k)
+		}
+	}
+
+	buff := &bytes.Buffer{}
+	encoder := json.NewEncoder(buff)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(fields); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
+
+// multipleStandardLoadBalancerConfigurations returns the configurations of the
+// load balancer of the cluster, which the cloud provider requires to be named
+// after the cluster, followed by the ones of the additional load balancers.
+func multipleStandardLoadBalancerConfigurations(clusterName string, lbs []azure.LoadBalancer) ([]MultipleStandardLoadBalancerConfiguration, error) {
+	if clusterName == "" {
+		return nil, errors.New("the cluster name is required to configure additional load balancers")
+	}
+	configs := []MultipleStandardLoadBalancerConfiguration{{Name: clusterName}}
+	for _, lb := range lbs {
+		if lb.Name == clusterName {
+			return nil, errors.Errorf("the additional load balancer %s has the name of the load balancer of the cluster", lb.Name)
+		}
+		configs = append(configs, MultipleStandardLoadBalancerConfiguration{
+			Name: lb.Name,
+			MultipleStandardLoadBalancerConfigurationSpec: MultipleStandardLoadBalancerConfigurationSpec{
+				PrimaryVMSet:             lb.PrimaryVMSet,
+				ServiceLabelSelector:     labelSelector(lb.ServiceLabels),
+				ServiceNamespaceSelector: labelSelector(lb.ServiceNamespaceLabels),
+				NodeSelector:             labelSelector(lb.NodeLabels),
+			},
+		})
+	}
+	return configs, nil
+}
+
+// labelSelector returns the selector matching the labels, or nil when there
+// are no labels, in which case the cloud provider matches everything.
+func labelSelector(labels map[string]string) *metav1.LabelSelector {
+	if len(labels) == 0 {
+		return nil
+	}
+	return &metav1.LabelSelector{MatchLabels: labels}
+}
+
+// cloudProviderTags returns the tags applied by the cloud provider in the
+// `a=b,c=d` format of the tags field, sorted by key. Tags with a `,` or `=`
+// cannot be represented there, in which case they are all returned as the
+// tagsMap field instead.
+func cloudProviderTags(tags map[string]string) (string, map[string]string) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+	keys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if strings.ContainsAny(k, ",=") || strings.ContainsAny(v, ",=") {
+			return "", tags
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+tags[k])
+	}
+	return strings.Join(pairs, ","), nil
+}
+
+// EndpointsJSON returns the endpoints of the Azure Stack environment for the
+// cloud provider config. The environment only has struct fields, which are
+// encoded in their declaration order, so the same environment always yields
+// the same bytes.
+func EndpointsJSON(env azureenv.Environment) (string, error) {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ResolveEnvironment returns the environment of the cloud from the registry of
+// environments of the Azure SDK, which for Azure Stack Hub is the file set with
+// AZURE_ENVIRONMENT_FILEPATH, so that the endpoints of the known non-public
+// clouds do not have to be discovered. The environment of the session is used
+// for the public cloud and for the clouds missing from the registry. The
+// registered environment must have an ARM endpoint.
+func ResolveEnvironment(cloudName azure.CloudEnvironment, sessionEnv azureenv.Environment) (azureenv.Environment, error) {
+	if cloudName == azure.PublicCloud {
+		return sessionEnv, nil
+	}
+	env, err := azureenv.EnvironmentFromName(cloudName.Name())
+	if err != nil {
+		logrus.Debugf("Using the environment of the Azure session for the %s cloud: %v", cloudName, err)
+		return sessionEnv, nil
+	}
+	if env.ResourceManagerEndpoint == "" {
+		return env, errors.Errorf("the registered environment of the %s cloud has no ARM endpoint", cloudName)
+	}
+	return env, nil
+}
+
+// routeTableName returns the name of the route table of the nodes, which is
+// the one of the existing route table if any, and is named after the resource
+// prefix otherwise.
+func (params CloudProviderConfig) routeTableName() string {
+	if params.RouteTableName != "" {
+		return params.RouteTableName
+	}
+	return params.ResourcePrefix + "-node-routetable"
+}
+
+// ValidateNetworking checks the networking of the install config against the
+// settings of the cloud provider config, so that combinations the cloud provider
+// does not support fail before the install rather than in the cluster.
+func ValidateNetworking(ic *types.InstallConfig) error {
+	if ic.Platform.Azure.CloudName != azure.StackCloud {
+		return nil
+	}
+	// The cloud provider config uses the basic load balancer SKU on Azure Stack Hub.
+	if ic.Platform.Azure.OutboundType == azure.NatGatewayOutboundType {
+		return errors.Errorf("outboundType %s is not compatible with %s, the basic load balancer SKU used by the cloud provider does not support NAT gateways", ic.Platform.Azure.OutboundType, azure.StackCloud)
+	}
+	if ic.Networking != nil {
+		var cidrs []ipnet.IPNet
+		for _, network := range ic.Networking.MachineNetwork {
+			cidrs = append(cidrs, network.CIDR)
+		}
+		for _, network := range ic.Networking.ClusterNetwork {
+			cidrs = append(cidrs, network.CIDR)
+		}
+		cidrs = append(cidrs, ic.Networking.ServiceNetwork...)
+		for _, cidr := range cidrs {
+			if cidr.IP.To4() == nil {
+				return errors.Errorf("IPv6 network %s is not compatible with %s, the basic load balancer SKU used by the cloud provider does not support IPv6", cidr.String(), azure.StackCloud)
+			}
+		}
+	}
+	return nil
+}
+
+// VirtualNetworkAndComputeSubnet returns the names of the virtual network and
+// the compute subnet for the cloud provider config. The default subnet only
+// exists in the virtual network created by the installer, so an existing
+// virtual network and compute subnet must be set together.
+func VirtualNetworkAndComputeSubnet(p *azure.Platform, infraID string) (string, string, error) {
+	switch {
+	case p.VirtualNetwork != "" && p.ComputeSubnet == "":
+		return "", "", errors.Errorf("computeSubnet must be set with the existing virtual network %s, the default subnet %s is only created in the virtual network of the cluster", p.VirtualNetwork, p.ComputeSubnetName(infraID))
+	case p.VirtualNetwork == "" && p.ComputeSubnet != "":
+		return "", "", errors.Errorf("virtualNetwork must be set with the existing compute subnet %s, the virtual network %s of the cluster only has the default subnets", p.ComputeSubnet, p.VirtualNetworkName(infraID))
+	}
+	return p.VirtualNetworkName(infraID), p.ComputeSubnetName(infraID), nil
+}
@@ -1,10 +1,16 @@
 package azure
 
 import (
+	"encoding/json"
+	"os"
+	"path/filepath"
 	"testing"
 
+	azureenv "github.com/Azure/go-autorest/autorest/azure"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
 	"github.com/openshift/installer/pkg/types/azure"
 )
 
@@ -54,3 +60,638 @@ func TestCloudProviderConfig(t *testing.T) {
 	assert.NoError(t, err, "failed to create cloud provider config")
 	assert.Equal(t, expected, json, "unexpected cloud provider config")
 }
+
+func TestCloudProviderConfigMinimal(t *testing.T) {
+	params := CloudProviderConfig{
+		CloudName:                azure.PublicCloud,
+		ResourceGroupName:        "clusterid-rg",
+		GroupLocation:            "westeurope",
+		ResourcePrefix:           "clusterid",
+		SubscriptionID:           "subID",
+		TenantID:                 "tenantID",
+		NetworkResourceGroupName: "clusterid-rg",
+		NetworkSecurityGroupName: "clusterid-node-nsg",
+		VirtualNetworkName:       "clusterid-vnet",
+		SubnetName:               "clusterid-worker-subnet",
+	}
+	full, err := params.JSON()
+	if !assert.NoError(t, err, "failed to create cloud provider config") {
+		return
+	}
+
+	params.Minimal = true
+	expected := `{
+	"cloud": "AzurePublicCloud",
+	"cloudProviderBackoff": true,
+	"cloudProviderBackoffDuration": 6,
+	"excludeMasterFromStandardLB": false,
+	"loadBalancerSku": "standard",
+	"location": "westeurope",
+	"resourceGroup": "clusterid-rg",
+	"routeTableName": "clusterid-node-routetable",
+	"securityGroupName": "clusterid-node-nsg",
+	"subnetName": "clusterid-worker-subnet",
+	"subscriptionId": "subID",
+	"tenantId": "tenantID",
+	"useInstanceMetadata": true,
+	"useManagedIdentityExtension": true,
+	"vmType": "standard",
+	"vnetName": "clusterid-vnet",
+	"vnetResourceGroup": "clusterid-rg"
+}
+`
+	minimal, err := params.JSON()
+	if !assert.NoError(t, err, "failed to create cloud provider config") {
+		return
+	}
+	assert.Equal(t, expected, minimal, "unexpected cloud provider config")
+
+	// The omitted fields are decoded to the same values as when emitted.
+	var fullConfig, minimalConfig config
+	if assert.NoError(t, json.Unmarshal([]byte(full), &fullConfig)) && assert.NoError(t, json.Unmarshal([]byte(minimal), &minimalConfig)) {
+		assert.Equal(t, fullConfig, minimalConfig)
+	}
+}
+
+func TestCloudProviderConfigMinimalStack(t *testing.T) {
+	config := CloudProviderConfig{
+		CloudName:               azure.StackCloud,
+		ResourcePrefix:          "clusterid",
+		ResourceManagerEndpoint: "https://management.local.azurestack.external",
+		PutVMSSVMBatchSize:      10,
+		Minimal:                 true,
+	}
+	minimal, err := config.JSON()
+	if !assert.NoError(t, err, "failed to create cloud provider config") {
+		return
+	}
+	assert.NotContains(t, minimal, "useManagedIdentityExtension")
+	assert.Contains(t, minimal, "\"putVMSSVMBatchSize\": 10,")
+	assert.Contains(t, minimal, "\"resourceManagerEndpoint\": \"https://management.local.azurestack.external\",")
+}
+
+func TestCloudProviderConfigPutVMSSVMBatchSize(t *testing.T) {
+	config := CloudProviderConfig{
+		CloudName:          azure.PublicCloud,
+		ResourcePrefix:     "clusterid",
+		PutVMSSVMBatchSize: 10,
+	}
+
+	json, err := config.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, "\"putVMSSVMBatchSize\": 10,", "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigLoadBalancerResourceGroup(t *testing.T) {
+	config := CloudProviderConfig{
+		CloudName:                 azure.PublicCloud,
+		ResourceGroupName:         "clusterid-rg",
+		ResourcePrefix:            "clusterid",
+		LoadBalancerResourceGroup: "lb-rg",
+	}
+
+	json, err := config.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, "\"resourceGroup\": \"clusterid-rg\",", "unexpected cloud provider config")
+	assert.Contains(t, json, "\"loadBalancerResourceGroup\": \"lb-rg\",", "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigRouteTableName(t *testing.T) {
+	config := CloudProviderConfig{
+		CloudName:         azure.PublicCloud,
+		ResourceGroupName: "clusterid-rg",
+		ResourcePrefix:    "clusterid",
+	}
+
+	json, err := config.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, "\"routeTableName\": \"clusterid-node-routetable\",", "unexpected cloud provider config")
+
+	config.RouteTableName = "byo-routes"
+	json, err = config.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, "\"routeTableName\": \"byo-routes\",", "unexpected cloud provider config")
+	assert.NotContains(t, json, "node-routetable", "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigLoadBalancerSku(t *testing.T) {
+	cases := []struct {
+		name          string
+		cloudName     azure.CloudEnvironment
+		sku           string
+		expected      string
+		expectedError string
+	}{{
+		name:     "default",
+		expected: "\"loadBalancerSku\": \"standard\",",
+	}, {
+		name:     "standard",
+		sku:      "standard",
+		expected: "\"loadBalancerSku\": \"standard\",",
+	}, {
+		name:     "basic",
+		sku:      "basic",
+		expected: "\"loadBalancerSku\": \"basic\",",
+	}, {
+		name:      "azure stack default",
+		cloudName: azure.StackCloud,
+		expected:  "\"loadBalancerSku\": \"basic\",",
+	}, {
+		name:          "unsupported",
+		sku:           "premium",
+		expectedError: `^unsupported load balancer SKU "premium"`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := CloudProviderConfig{
+				CloudName:       azure.PublicCloud,
+				ResourcePrefix:  "clusterid",
+				LoadBalancerSku: tc.sku,
+			}
+			if tc.cloudName != "" {
+				config.CloudName = tc.cloudName
+			}
+			if tc.cloudName == azure.StackCloud {
+				config.ResourceManagerEndpoint = "https://management.local.azurestack.external"
+			}
+
+			json, err := config.JSON()
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err, "failed to create cloud provider config")
+			assert.Contains(t, json, tc.expected, "unexpected cloud provider config")
+		})
+	}
+}
+
+func TestCloudProviderConfigServiceEndpoints(t *testing.T) {
+	cases := []struct {
+		name             string
+		serviceEndpoints []azure.ServiceEndpoint
+		expected         []string
+		expectedError    string
+	}{{
+		name: "no overrides",
+	}, {
+		name: "private link endpoints",
+		serviceEndpoints: []azure.ServiceEndpoint{{
+			Name: azure.ResourceManagerServiceEndpoint,
+			URL:  "https://management.privatelink.azure.com",
+		}, {
+			Name: azure.ActiveDirectoryServiceEndpoint,
+			URL:  "https://login.privatelink.microsoftonline.com/",
+		}},
+		expected: []string{
+			"\"resourceManagerEndpoint\": \"https://management.privatelink.azure.com\",",
+			"\"activeDirectoryEndpoint\": \"https://login.privatelink.microsoftonline.com/\",",
+		},
+	}, {
+		name:             "unsupported service",
+		serviceEndpoints: []azure.ServiceEndpoint{{Name: "Storage", URL: "https://blob.privatelink.azure.com"}},
+		expectedError:    `^unsupported service endpoint "Storage", expected "ResourceManager" or "ActiveDirectory"$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := CloudProviderConfig{
+				CloudName:        azure.PublicCloud,
+				ResourcePrefix:   "clusterid",
+				ServiceEndpoints: tc.serviceEndpoints,
+			}
+
+			json, err := config.JSON()
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err, "failed to create cloud provider config")
+			if len(tc.expected) == 0 {
+				assert.NotContains(t, json, "resourceManagerEndpoint", "unexpected cloud provider config")
+				assert.NotContains(t, json, "activeDirectoryEndpoint", "unexpected cloud provider config")
+				return
+			}
+			for _, expected := range tc.expected {
+				assert.Contains(t, json, expected, "unexpected cloud provider config")
+			}
+		})
+	}
+}
+
+func TestCloudProviderConfigOutboundType(t *testing.T) {
+	cases := []struct {
+		name         string
+		outboundType azure.OutboundType
+		sku          string
+		expected     bool
+	}{{
+		name: "default",
+	}, {
+		name:         "load balancer",
+		outboundType: azure.LoadbalancerOutboundType,
+	}, {
+		name:         "user-defined routing",
+		outboundType: azure.UserDefinedRoutingOutboundType,
+		expected:     true,
+	}, {
+		name:         "nat gateway",
+		outboundType: azure.NatGatewayOutboundType,
+		expected:     true,
+	}, {
+		name:         "user-defined routing with basic load balancers",
+		outboundType: azure.UserDefinedRoutingOutboundType,
+		sku:          "basic",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := CloudProviderConfig{
+				CloudName:       azure.PublicCloud,
+				ResourcePrefix:  "clusterid",
+				OutboundType:    tc.outboundType,
+				LoadBalancerSku: tc.sku,
+			}
+
+			json, err := config.JSON()
+			assert.NoError(t, err, "failed to create cloud provider config")
+			if tc.expected {
+				assert.Contains(t, json, "\"disableOutboundSNAT\": true", "unexpected cloud provider config")
+			} else {
+				assert.NotContains(t, json, "disableOutboundSNAT", "unexpected cloud provider config")
+			}
+		})
+	}
+}
+
+func TestCloudProviderConfigTags(t *testing.T) {
+	cases := []struct {
+		name        string
+		tags        map[string]string
+		expected    string
+		notExpected []string
+	}{{
+		name:        "no tags",
+		notExpected: []string{"\"tags\"", "\"tagsMap\""},
+	}, {
+		name:        "tags",
+		tags:        map[string]string{"owner": "team-a", "cost-center": "1234"},
+		expected:    "\"tags\": \"cost-center=1234,owner=team-a\",",
+		notExpected: []string{"\"tagsMap\""},
+	}, {
+		name:        "tags with special characters",
+		tags:        map[string]string{"owner": "team=a"},
+		expected:    "\"tagsMap\": {\n\t\t\"owner\": \"team=a\"\n\t},",
+		notExpected: []string{"\"tags\""},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := CloudProviderConfig{
+				CloudName:      azure.PublicCloud,
+				ResourcePrefix: "clusterid",
+				Tags:           tc.tags,
+			}
+
+			json, err := config.JSON()
+			assert.NoError(t, err, "failed to create cloud provider config")
+			if tc.expected != "" {
+				assert.Contains(t, json, tc.expected, "unexpected cloud provider config")
+			}
+			for _, notExpected := range tc.notExpected {
+				assert.NotContains(t, json, notExpected, "unexpected cloud provider config")
+			}
+		})
+	}
+}
+
+func TestCloudProviderConfigLoadBalancers(t *testing.T) {
+	single := CloudProviderConfig{
+		CloudName:      azure.PublicCloud,
+		ResourcePrefix: "clusterid",
+		ClusterName:    "clusterid",
+	}
+	json, err := single.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.NotContains(t, json, "multipleStandardLoadBalancerConfigurations", "unexpected cloud provider config")
+	assert.NotContains(t, json, "loadBalancerBackendPoolConfigurationType", "unexpected cloud provider config")
+
+	multiple := single
+	multiple.LoadBalancers = []azure.LoadBalancer{{
+		Name:          "lb-a",
+		PrimaryVMSet:  "set-a",
+		ServiceLabels: map[string]string{"lb": "a"},
+	}}
+	json, err = multiple.JSON()
+	if !assert.NoError(t, err, "failed to create cloud provider config") {
+		return
+	}
+	assert.Contains(t, json, "\"loadBalancerBackendPoolConfigurationType\": \"nodeIP\",", "unexpected cloud provider config")
+	assert.Contains(t, json, "\"name\": \"clusterid\",", "unexpected cloud provider config")
+	assert.Contains(t, json, "\"name\": \"lb-a\",\n\t\t\t\"allowServicePlacement\": null,\n\t\t\t\"primaryVMSet\": \"set-a\",\n\t\t\t\"serviceLabelSelector\": {\n\t\t\t\t\"matchLabels\": {\n\t\t\t\t\t\"lb\": \"a\"\n\t\t\t\t}\n\t\t\t},", "unexpected cloud provider config")
+
+	basic := multiple
+	basic.LoadBalancerSku = string(azure.BasicLoadBalancerSKU)
+	_, err = basic.JSON()
+	assert.EqualError(t, err, "additional load balancers are not supported with the basic load balancer SKU")
+
+	unnamed := multiple
+	unnamed.ClusterName = ""
+	_, err = unnamed.JSON()
+	assert.EqualError(t, err, "the cluster name is required to configure additional load balancers")
+
+	clashing := multiple
+	clashing.LoadBalancers = []azure.LoadBalancer{{Name: "clusterid"}}
+	_, err = clashing.JSON()
+	assert.EqualError(t, err, "the additional load balancer clusterid has the name of the load balancer of the cluster")
+}
+
+func TestCloudProviderConfigAvailabilitySet(t *testing.T) {
+	zonal := CloudProviderConfig{
+		CloudName:      azure.PublicCloud,
+		ResourcePrefix: "clusterid",
+	}
+	json, err := zonal.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, "\"vmType\": \"standard\",", "unexpected cloud provider config")
+	assert.NotContains(t, json, "primaryAvailabilitySetName", "unexpected cloud provider config")
+
+	availabilitySet := CloudProviderConfig{
+		CloudName:                  azure.PublicCloud,
+		ResourcePrefix:             "clusterid",
+		VMType:                     "standard",
+		PrimaryAvailabilitySetName: "clusterid-cluster",
+	}
+	json, err = availabilitySet.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, "\"primaryAvailabilitySetName\": \"clusterid-cluster\",", "unexpected cloud provider config")
+	assert.Contains(t, json, "\"vmType\": \"standard\",", "unexpected cloud provider config")
+
+	vmss := CloudProviderConfig{
+		CloudName:      azure.PublicCloud,
+		ResourcePrefix: "clusterid",
+		VMType:         "vmss",
+	}
+	json, err = vmss.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, "\"vmType\": \"vmss\",", "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigStackEndpoints(t *testing.T) {
+	config := CloudProviderConfig{
+		CloudName:               azure.StackCloud,
+		ResourceGroupName:       "clusterid-rg",
+		GroupLocation:           "local",
+		ResourcePrefix:          "clusterid",
+		SubscriptionID:          "subID",
+		TenantID:                "tenantID",
+		ResourceManagerEndpoint: "https://management.local.azurestack.external",
+		ActiveDirectoryEndpoint: "https://login.microsoftonline.com/",
+		GraphEndpoint:           "https://graph.windows.net/",
+		GalleryEndpoint:         "https://portal.local.azurestack.external:30015/",
+	}
+
+	json, err := config.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, json, `	"resourceManagerEndpoint": "https://management.local.azurestack.external",
+	"activeDirectoryEndpoint": "https://login.microsoftonline.com/",
+	"graphEndpoint": "https://graph.windows.net/",
+	"galleryEndpoint": "https://portal.local.azurestack.external:30015/",
+`, "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigStackARMEndpoint(t *testing.T) {
+	cases := []struct {
+		name          string
+		endpoint      string
+		expectedError string
+	}{{
+		name:     "valid",
+		endpoint: "https://management.local.azurestack.external",
+	}, {
+		name:          "missing",
+		expectedError: `^invalid ARM endpoint "": ARM endpoint must be set$`,
+	}, {
+		name:          "http",
+		endpoint:      "http://management.local.azurestack.external",
+		expectedError: `^invalid ARM endpoint "http://management.local.azurestack.external": only https scheme is allowed$`,
+	}, {
+		name:          "without host",
+		endpoint:      "https:///metadata",
+		expectedError: `^invalid ARM endpoint "https:///metadata": host cannot be empty$`,
+	}, {
+		name:          "malformed",
+		endpoint:      "https://management local",
+		expectedError: `^invalid ARM endpoint "https://management local": parse "https://management local": invalid character " " in host name$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := CloudProviderConfig{
+				CloudName:               azure.StackCloud,
+				ResourcePrefix:          "clusterid",
+				ResourceManagerEndpoint: tc.endpoint,
+			}
+			_, err := config.JSON()
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Regexp(t, tc.expectedError, err)
+		})
+	}
+}
+
+func TestCloudProviderConfigPublicIgnoresStackEndpoints(t *testing.T) {
+	config := CloudProviderConfig{
+		CloudName:               azure.PublicCloud,
+		ResourcePrefix:          "clusterid",
+		ActiveDirectoryEndpoint: "https://login.microsoftonline.com/",
+		GraphEndpoint:           "https://graph.windows.net/",
+		GalleryEndpoint:         "https://gallery.azure.com/",
+	}
+
+	json, err := config.JSON()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.NotContains(t, json, "activeDirectoryEndpoint", "unexpected cloud provider config")
+	assert.NotContains(t, json, "graphEndpoint", "unexpected cloud provider config")
+	assert.NotContains(t, json, "galleryEndpoint", "unexpected cloud provider config")
+}
+
+func TestEndpointsJSON(t *testing.T) {
+	env := azureenv.Environment{
+		Name:                    "AzureStackCloud",
+		ResourceManagerEndpoint: "https://management.local.azurestack.external/",
+		ActiveDirectoryEndpoint: "https://login.microsoftonline.com/",
+		GraphEndpoint:           "https://graph.windows.net/",
+		ResourceIdentifiers: azureenv.ResourceIdentifier{
+			Graph:   "https://graph.windows.net/",
+			Storage: "https://storage.azure.com/",
+		},
+	}
+
+	first, err := EndpointsJSON(env)
+	assert.NoError(t, err, "failed to serialize the endpoints")
+	second, err := EndpointsJSON(env)
+	assert.NoError(t, err, "failed to serialize the endpoints")
+	assert.Equal(t, []byte(first), []byte(second), "the endpoints differ between runs")
+	assert.Contains(t, first, `{"name":"AzureStackCloud","managementPortalURL":"","publishSettingsURL":"","serviceManagementEndpoint":"","resourceManagerEndpoint":"https://management.local.azurestack.external/","activeDirectoryEndpoint":"https://login.microsoftonline.com/",`)
+}
+
+func TestResolveEnvironment(t *testing.T) {
+	sessionEnv := azureenv.Environment{
+		Name:                    "AzureStackCloud",
+		ResourceManagerEndpoint: "https://management.local.azurestack.external/",
+		ActiveDirectoryEndpoint: "https://login.local.azurestack.external/",
+	}
+	registeredEnv := `{"name": "AzureStackCloud", "resourceManagerEndpoint": "https://management.registered.azurestack.external/", "activeDirectoryEndpoint": "https://login.registered.azurestack.external/"}`
+
+	cases := []struct {
+		name                string
+		cloudName           azure.CloudEnvironment
+		environmentFile     string
+		sessionEnv          azureenv.Environment
+		expectedARMEndpoint string
+		expectedError       string
+	}{{
+		name:                "public",
+		cloudName:           azure.PublicCloud,
+		sessionEnv:          azureenv.PublicCloud,
+		expectedARMEndpoint: "https://management.azure.com/",
+	}, {
+		name:                "sovereign",
+		cloudName:           azure.USGovernmentCloud,
+		expectedARMEndpoint: "https://management.usgovcloudapi.net/",
+	}, {
+		name:                "registered stack",
+		cloudName:           azure.StackCloud,
+		environmentFile:     registeredEnv,
+		sessionEnv:          sessionEnv,
+		expectedARMEndpoint: "https://management.registered.azurestack.external/",
+	}, {
+		name:                "unregistered stack",
+		cloudName:           azure.StackCloud,
+		sessionEnv:          sessionEnv,
+		expectedARMEndpoint: "https://management.local.azurestack.external/",
+	}, {
+		name:            "registered stack without ARM endpoint",
+		cloudName:       azure.StackCloud,
+		environmentFile: `{"name": "AzureStackCloud", "activeDirectoryEndpoint": "https://login.registered.azurestack.external/"}`,
+		sessionEnv:      sessionEnv,
+		expectedError:   "the registered environment of the AzureStackCloud cloud has no ARM endpoint",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			environmentFile := ""
+			if tc.environmentFile != "" {
+				environmentFile = filepath.Join(t.TempDir(), "environment.json")
+				if !assert.NoError(t, os.WriteFile(environmentFile, []byte(tc.environmentFile), 0o600)) {
+					return
+				}
+			}
+			t.Setenv(azureenv.EnvironmentFilepathName, environmentFile)
+
+			env, err := ResolveEnvironment(tc.cloudName, tc.sessionEnv)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedARMEndpoint, env.ResourceManagerEndpoint)
+			}
+		})
+	}
+}
+
+func TestVirtualNetworkAndComputeSubnet(t *testing.T) {
+	cases := []struct {
+		name           string
+		virtualNetwork string
+		computeSubnet  string
+		expectedVNet   string
+		expectedSubnet string
+		expectedError  string
+	}{{
+		name:           "defaults",
+		expectedVNet:   "clusterid-vnet",
+		expectedSubnet: "clusterid-worker-subnet",
+	}, {
+		name:           "existing virtual network and subnet",
+		virtualNetwork: "vnet",
+		computeSubnet:  "compute",
+		expectedVNet:   "vnet",
+		expectedSubnet: "compute",
+	}, {
+		name:           "existing virtual network only",
+		virtualNetwork: "vnet",
+		expectedError:  `^computeSubnet must be set with the existing virtual network vnet, the default subnet clusterid-worker-subnet is only created in the virtual network of the cluster$`,
+	}, {
+		name:          "existing compute subnet only",
+		computeSubnet: "compute",
+		expectedError: `^virtualNetwork must be set with the existing compute subnet compute, the virtual network clusterid-vnet of the cluster only has the default subnets$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &azure.Platform{VirtualNetwork: tc.virtualNetwork, ComputeSubnet: tc.computeSubnet}
+			vnet, subnet, err := VirtualNetworkAndComputeSubnet(p, "clusterid")
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedVNet, vnet)
+			assert.Equal(t, tc.expectedSubnet, subnet)
+		})
+	}
+}
+
+func TestValidateNetworking(t *testing.T) {
+	cases := []struct {
+		name          string
+		cloudName     azure.CloudEnvironment
+		outboundType  azure.OutboundType
+		machineCIDRs  []string
+		expectedError string
+	}{{
+		name:         "public cloud with NAT gateway",
+		cloudName:    azure.PublicCloud,
+		outboundType: azure.NatGatewayOutboundType,
+	}, {
+		name:         "public cloud with dual-stack",
+		cloudName:    azure.PublicCloud,
+		machineCIDRs: []string{"10.0.0.0/16", "fd00::/48"},
+	}, {
+		name:         "stack cloud",
+		cloudName:    azure.StackCloud,
+		outboundType: azure.LoadbalancerOutboundType,
+		machineCIDRs: []string{"10.0.0.0/16"},
+	}, {
+		name:          "stack cloud with NAT gateway",
+		cloudName:     azure.StackCloud,
+		outboundType:  azure.NatGatewayOutboundType,
+		expectedError: `^outboundType NatGateway is not compatible with AzureStackCloud, the basic load balancer SKU used by the cloud provider does not support NAT gateways$`,
+	}, {
+		name:          "stack cloud with dual-stack",
+		cloudName:     azure.StackCloud,
+		machineCIDRs:  []string{"10.0.0.0/16", "fd00::/48"},
+		expectedError: `^IPv6 network fd00::/48 is not compatible with AzureStackCloud, the basic load balancer SKU used by the cloud provider does not support IPv6$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				Networking: &types.Networking{},
+				Platform: types.Platform{
+					Azure: &azure.Platform{
+						CloudName:    tc.cloudName,
+						OutboundType: tc.outboundType,
+					},
+				},
+			}
+			for _, cidr := range tc.machineCIDRs {
+				ic.Networking.MachineNetwork = append(ic.Networking.MachineNetwork, types.MachineNetworkEntry{CIDR: *ipnet.MustParseCIDR(cidr)})
+			}
+			err := ValidateNetworking(ic)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Regexp(t, tc.expectedError, err)
+			}
+		})
+	}
+}
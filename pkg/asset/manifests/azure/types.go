@@ -31,6 +31,13 @@ type authConfig struct {
 	// ResourceManagerEndpoint is the cloud's resource manager endpoint. If set, cloud provider queries this endpoint
 	// in order to generate an autorest.Environment instance instead of using one of the pre-defined Environments.
 	ResourceManagerEndpoint string `json:"resourceManagerEndpoint,omitempty" yaml:"resourceManagerEndpoint,omitempty"`
+	// ActiveDirectoryEndpoint is the cloud's active directory endpoint, only needed for clouds without a
+	// pre-defined Environment.
+	ActiveDirectoryEndpoint string `json:"activeDirectoryEndpoint,omitempty" yaml:"activeDirectoryEndpoint,omitempty"`
+	// GraphEndpoint is the cloud's graph endpoint, only needed for clouds without a pre-defined Environment.
+	GraphEndpoint string `json:"graphEndpoint,omitempty" yaml:"graphEndpoint,omitempty"`
+	// GalleryEndpoint is the cloud's gallery endpoint, only needed for clouds without a pre-defined Environment.
+	GalleryEndpoint string `json:"galleryEndpoint,omitempty" yaml:"galleryEndpoint,omitempty"`
 }
 
 // config is the cloud provider config as defined in https://raw.githubusercontent.com/openshift/cloud-provider-azure/75ed9a21c1f0e2acfb5b27da395fdb02c918d56f/pkg/provider/azure.go
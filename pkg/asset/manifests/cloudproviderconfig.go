@@ -1,28 +1,37 @@
 package manifests
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/yaml"
 
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/api/features"
 	"github.com/openshift/installer/pkg/asset"
 	"github.com/openshift/installer/pkg/asset/installconfig"
-	ibmcloudmachines "github.com/openshift/installer/pkg/asset/machines/ibmcloud"
-	"github.com/openshift/installer/pkg/asset/manifests/azure"
-	"github.com/openshift/installer/pkg/asset/manifests/capiutils"
-	gcpmanifests "github.com/openshift/installer/pkg/asset/manifests/gcp"
-	ibmcloudmanifests "github.com/openshift/installer/pkg/asset/manifests/ibmcloud"
-	nutanixmanifests "github.com/openshift/installer/pkg/asset/manifests/nutanix"
-	openstackmanifests "github.com/openshift/installer/pkg/asset/manifests/openstack"
-	powervsmanifests "github.com/openshift/installer/pkg/asset/manifests/powervs"
-	vspheremanifests "github.com/openshift/installer/pkg/asset/manifests/vsphere"
+	icazure "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	"github.com/openshift/installer/pkg/types"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
 	azuretypes "github.com/openshift/installer/pkg/types/azure"
 	baremetaltypes "github.com/openshift/installer/pkg/types/baremetal"
@@ -38,22 +47,287 @@ import (
 )
 
 var (
-	cloudProviderConfigFileName = filepath.Join(manifestDir, "cloud-provider-config.yaml")
+	cloudProviderConfigFileName = filepath.Join(manifestDir, cloudProviderConfigName+".yaml")
+
+	// platformsWithCloudProviderConfig are the platforms for which a cloud
+	// provider config is generated. Each of them must be handled by
+	// BuildCloudProviderConfigMap.
+	platformsWithCloudProviderConfig = sets.New(
+		awstypes.Name,
+		azuretypes.Name,
+		gcptypes.Name,
+		ibmcloudtypes.Name,
+		nutanixtypes.Name,
+		openstacktypes.Name,
+		powervstypes.Name,
+		vspheretypes.Name,
+	)
+
+	// platformsWithoutCloudProviderConfig are the platforms for which no cloud
+	// provider config is generated.
+	platformsWithoutCloudProviderConfig = sets.New(
+		baremetaltypes.Name,
+		externaltypes.Name,
+		nonetypes.Name,
+		ovirttypes.Name,
+	)
 )
 
+// PlatformsWithCloudProviderConfig returns the sorted names of the platforms for
+// which a cloud provider config is generated.
+func PlatformsWithCloudProviderConfig() []string {
+	return sets.List(platformsWithCloudProviderConfig)
+}
+
+// PlatformsWithoutCloudProviderConfig returns the sorted names of the platforms
+// for which no cloud provider config is generated.
+func PlatformsWithoutCloudProviderConfig() []string {
+	return sets.List(platformsWithoutCloudProviderConfig)
+}
+
+// externalCloudProviderPreviewEnabled returns whether the external cloud
+// providers are enabled by a feature set other than the default one.
+func externalCloudProviderPreviewEnabled(ic *types.InstallConfig) bool {
+	if ic.FeatureSet == configv1.Default {
+		return false
+	}
+	return ic.EnabledFeatureGates().Enabled(features.FeatureGateExternalCloudProvider)
+}
+
+// hasExternalCloudProvider returns whether the external platform names the
+// cloud provider run by its external cloud controller manager, which reads the
+// cloud provider config.
+func hasExternalCloudProvider(ic *types.InstallConfig) bool {
+	return ic.Platform.External != nil && ic.Platform.External.CloudProviderName != ""
+}
+
+// usesCloudProviderConfigMap returns whether a cloud provider config ConfigMap
+// is built for the install config, which is the case for the platforms with a
+// cloud provider config, and for the others once external cloud providers are
+// enabled.
+func usesCloudProviderConfigMap(ic *types.InstallConfig) bool {
+	return !platformsWithoutCloudProviderConfig.Has(ic.Platform.Name()) || externalCloudProviderPreviewEnabled(ic) || hasExternalCloudProvider(ic)
+}
+
+// ProducesCloudProviderConfig returns whether a cloud provider config is
+// generated for the platform.
+func ProducesCloudProviderConfig(platformName string) bool {
+	return platformsWithCloudProviderConfig.Has(platformName)
+}
+
 const (
-	cloudProviderConfigDataKey         = "config"
-	cloudProviderConfigCABundleDataKey = "ca-bundle.pem"
-	cloudProviderEndpointsKey          = "endpoints"
+	// ConfigDataKey is the key of the cloud provider config in the data of
+	// the ConfigMap.
+	ConfigDataKey = "config"
+
+	// CABundleDataKey is the key of the CA bundle of the cloud in the data of
+	// the ConfigMap.
+	CABundleDataKey = "ca-bundle.pem"
+
+	// EndpointsKey is the key of the endpoints of the Azure Stack Hub cloud in
+	// the data of the ConfigMap.
+	EndpointsKey = "endpoints"
+
+	// ProviderDataKey is the key of the name of the external cloud provider in
+	// the data of the ConfigMap.
+	ProviderDataKey = "provider"
+
+	// ExternalConfigDataKey is the key of the config for the external cloud
+	// controller manager in the data of the ConfigMap, for the platforms whose
+	// external config differs from the in-tree one. It is only set while the
+	// external cloud provider is in preview, for the clusters transitioning
+	// to it, and will be removed once the transition is over.
+	ExternalConfigDataKey = "external-config"
 )
 
+// cloudProviderConfigName is the name of the cloud provider config ConfigMap
+// and of its manifest.
+const cloudProviderConfigName = "cloud-provider-config"
+
+const (
+	// cloudProviderConfigFeatureSetAnnotation records the feature set the
+	// cloud provider config was generated for.
+	cloudProviderConfigFeatureSetAnnotation = "installer.openshift.io/feature-set"
+
+	// cloudProviderConfigImageMirrorsAnnotation records the image mirrors of
+	// disconnected installs, which the cloud controllers pull their images from.
+	cloudProviderConfigImageMirrorsAnnotation = "installer.openshift.io/image-mirrors"
+
+	// cloudProviderConfigOfflineAnnotation marks the cloud provider configs
+	// generated offline, which hold placeholders instead of the values
+	// looked up from the cloud.
+	cloudProviderConfigOfflineAnnotation = "installer.openshift.io/offline"
+
+	// cloudProviderConfigChecksumAnnotation records the checksum of the data
+	// of the generated ConfigMap, to detect edits made out of band.
+	cloudProviderConfigChecksumAnnotation = "installer.openshift.io/config-checksum"
+
+	// offlinePlaceholder replaces the values which are looked up from the
+	// cloud when the cloud provider config is generated offline.
+	offlinePlaceholder = "OFFLINE-PLACEHOLDER"
+
+	// maxCloudProviderConfigDataSize is the maximum total size of the keys and
+	// values stored in a ConfigMap, as enforced by the Kubernetes API server.
+	maxCloudProviderConfigDataSize = 1024 * 1024
+
+	// defaultIBMCloudAccountIDTimeout is the default time to wait for the IBM
+	// Cloud account ID lookup.
+	defaultIBMCloudAccountIDTimeout = 30 * time.Second
+
+	// azureSessionRetrySteps is the number of attempts to get the Azure session
+	// when the authentication is throttled or the endpoint is unavailable.
+	azureSessionRetrySteps = 5
+	// azureSessionRetryInterval is the wait before the first retry, which is
+	// doubled for each retry after it.
+	azureSessionRetryInterval = 2 * time.Second
+)
+
+// azureSessionBackoff is the backoff of the retries of the Azure session.
+var azureSessionBackoff = wait.Backoff{
+	Duration: azureSessionRetryInterval,
+	Factor:   2,
+	Steps:    azureSessionRetrySteps,
+}
+
 // CloudProviderConfig generates the cloud-provider-config.yaml files.
+// Distinct assets can be generated concurrently, including ones sharing an
+// Azure session, since all the state of a generation is local to it.
 type CloudProviderConfig struct {
 	ConfigMap *corev1.ConfigMap
 	File      *asset.File
+
+	// NotApplicable is set once generated for a platform which does not use
+	// a cloud provider config, to tell it apart from an asset which was not
+	// generated yet. ConfigMap and File are nil then.
+	NotApplicable bool
+
+	// generatedFrom is the fingerprint of the parent assets the asset was
+	// last generated from. It is empty once loaded or reset.
+	generatedFrom string
+
+	options []CloudProviderConfigOption
 }
 
-var _ asset.WritableAsset = (*CloudProviderConfig)(nil)
+var (
+	_ asset.WritableAsset = (*CloudProviderConfig)(nil)
+	_ asset.FileWriter    = (*CloudProviderConfig)(nil)
+)
+
+// CloudProviderConfigOption customizes how the cloud provider config is built.
+type CloudProviderConfigOption func(*cloudProviderConfigOptions)
+
+type cloudProviderConfigOptions struct {
+	azureSession              *icazure.Session
+	ibmcloudAccountIDResolver accountIDResolver
+	openstackCloudsSecret     *secretReference
+	dataOverrides             map[string]interface{}
+	manifestDir               string
+	strictLoad                bool
+	minimalAzureConfig        bool
+	requireConfig             bool
+	caBundle                  string
+}
+
+// accountIDResolver returns the ID of the account the credentials of the
+// installer belong to.
+type accountIDResolver interface {
+	AccountID(ctx context.Context) (string, error)
+}
+
+// WithAzureSession makes the cloud provider config for Azure use the given
+// session instead of creating one from the install config. This allows
+// callers which already hold authenticated credentials to provide them.
+func WithAzureSession(session *icazure.Session) CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.azureSession = session
+	}
+}
+
+// SecretFetcher fetches the Secrets which the cloud provider config reads the
+// credentials of the cloud from.
+type SecretFetcher interface {
+	GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error)
+}
+
+// secretReference is a Secret along with the fetcher to get it with.
+type secretReference struct {
+	namespace string
+	name      string
+	fetcher   SecretFetcher
+}
+
+// WithOpenStackCloudsSecret makes the cloud provider config for OpenStack read
+// the clouds.yaml key of the given Secret instead of the clouds.yaml files on
+// disk, for callers which keep the credentials in a Secret.
+func WithOpenStackCloudsSecret(namespace, name string, fetcher SecretFetcher) CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.openstackCloudsSecret = &secretReference{namespace: namespace, name: name, fetcher: fetcher}
+	}
+}
+
+// WithManifestDir makes the asset write the cloud provider config in the
+// given directory instead of the manifests directory, and load it from there.
+func WithManifestDir(dir string) CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.manifestDir = dir
+	}
+}
+
+// WithStrictLoad makes Load fail when the loaded ConfigMap holds data keys
+// which the cloud providers do not read, instead of only warning about them.
+func WithStrictLoad() CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.strictLoad = true
+	}
+}
+
+// WithMinimalAzureConfig makes the cloud provider config for Azure omit the
+// fields which hold the defaults of the cloud provider, e.g. the unset client
+// credentials, so that the config is smaller and its diffs only show changes.
+func WithMinimalAzureConfig() CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.minimalAzureConfig = true
+	}
+}
+
+// WithRequiredConfig makes Generate fail on the platforms which do not use a
+// cloud provider config instead of generating none, for pipelines where every
+// cluster must ship one and a config-less platform is a misconfiguration.
+func WithRequiredConfig() CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.requireConfig = true
+	}
+}
+
+// withIBMCloudAccountIDResolver makes the cloud provider config for IBM Cloud
+// get the account ID from the given resolver instead of the install config.
+func withIBMCloudAccountIDResolver(resolver accountIDResolver) CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.ibmcloudAccountIDResolver = resolver
+	}
+}
+
+// withDataOverrides applies the overrides over the generated data of the
+// cloud provider config.
+func withDataOverrides(overrides map[string]interface{}) CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.dataOverrides = overrides
+	}
+}
+
+// withCABundle sets the CA bundle of the cloud provider config in place of
+// the generated one, if any.
+func withCABundle(bundle string) CloudProviderConfigOption {
+	return func(o *cloudProviderConfigOptions) {
+		o.caBundle = bundle
+	}
+}
+
+// NewCloudProviderConfig returns a CloudProviderConfig asset which is
+// generated with the given options.
+func NewCloudProviderConfig(opts ...CloudProviderConfigOption) *CloudProviderConfig {
+	return &CloudProviderConfig{options: opts}
+}
 
 // Name returns a human friendly name for the asset.
 func (*CloudProviderConfig) Name() string {
@@ -66,6 +340,8 @@ func (*CloudProviderConfig) Dependencies() []asset.Asset {
 	return []asset.Asset{
 		&installconfig.InstallConfig{},
 		&installconfig.ClusterID{},
+		&CloudProviderConfigOverrides{},
+		&CloudProviderConfigCABundle{},
 
 		// PlatformCredsCheck just checks the creds (and asks, if needed)
 		// We do not actually use it in this asset directly, hence
@@ -74,13 +350,151 @@ func (*CloudProviderConfig) Dependencies() []asset.Asset {
 	}
 }
 
-// Generate generates the CloudProviderConfig.
-//
-//nolint:gocyclo
+// Generate generates the CloudProviderConfig. Generating it again from the
+// same parent assets keeps the generated config, so that the cloud is not
+// called again, e.g. to prompt for the Azure device code.
 func (cpc *CloudProviderConfig) Generate(ctx context.Context, dependencies asset.Parents) error {
 	installConfig := &installconfig.InstallConfig{}
 	clusterID := &installconfig.ClusterID{}
-	dependencies.Get(installConfig, clusterID)
+	overrides := &CloudProviderConfigOverrides{}
+	caBundle := &CloudProviderConfigCABundle{}
+	dependencies.Get(installConfig, clusterID, overrides, caBundle)
+
+	fingerprint, err := cloudProviderConfigFingerprint(installConfig, clusterID, overrides, caBundle)
+	if err != nil {
+		return err
+	}
+	if fingerprint == cpc.generatedFrom && (cpc.File != nil || cpc.NotApplicable) {
+		logrus.Debugf("The parent assets of the %s are unchanged, keeping the generated config", cpc.Name())
+		return nil
+	}
+
+	cm, file, err := cpc.generateFile(ctx, installConfig, clusterID, withDataOverrides(overrides.Data), withCABundle(caBundle.Bundle))
+	if err != nil {
+		return err
+	}
+	if cm == nil && caBundle.Bundle != "" {
+		logrus.Warnf("Ignoring the CA bundle of the %s, the %s platform does not use a cloud provider config", cpc.Name(), installConfig.Config.Platform.Name())
+	}
+	if cm == nil && cpc.resolveOptions().requireConfig {
+		return errors.Errorf("the %s platform does not use a cloud provider config, but one is required", installConfig.Config.Platform.Name())
+	}
+	logCloudProviderConfigKeys(installConfig, clusterID, cm)
+	cpc.ConfigMap, cpc.File, cpc.NotApplicable = cm, file, cm == nil
+	cpc.generatedFrom = fingerprint
+	return nil
+}
+
+// cloudProviderConfigFingerprint returns the checksum of the parent assets the
+// cloud provider config is generated from.
+func cloudProviderConfigFingerprint(installConfig *installconfig.InstallConfig, clusterID *installconfig.ClusterID, overrides *CloudProviderConfigOverrides, caBundle *CloudProviderConfigCABundle) (string, error) {
+	data, err := json.Marshal(struct {
+		InstallConfig *types.InstallConfig
+		ClusterID     *installconfig.ClusterID
+		Overrides     map[string]interface{}
+		CABundle      string
+	}{installConfig.Config, clusterID, overrides.Data, caBundle.Bundle})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fingerprint the parent assets of the cloud provider config")
+	}
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data)), nil
+}
+
+// GenerateFile returns the manifest of the cloud provider config for the
+// install config without modifying the asset. The returned file is nil when
+// the platform does not use a cloud provider config. Unlike Generate, no
+// overrides from disk are applied, since they are a parent asset.
+func (cpc *CloudProviderConfig) GenerateFile(ctx context.Context, installConfig *installconfig.InstallConfig, clusterID *installconfig.ClusterID) (*asset.File, error) {
+	_, file, err := cpc.generateFile(ctx, installConfig, clusterID)
+	return file, err
+}
+
+// generateFile builds the ConfigMap along with the file of its manifest. Both
+// are nil when the platform does not use a cloud provider config.
+func (cpc *CloudProviderConfig) generateFile(ctx context.Context, installConfig *installconfig.InstallConfig, clusterID *installconfig.ClusterID, opts ...CloudProviderConfigOption) (*corev1.ConfigMap, *asset.File, error) {
+	cm, cmData, err := cpc.renderConfigMap(ctx, installConfig, clusterID, opts...)
+	if err != nil || cm == nil {
+		return nil, nil, err
+	}
+	return cm, &asset.File{
+		Filename: cpc.fileName(),
+		Data:     cmData,
+	}, nil
+}
+
+// logCloudProviderConfigKeys logs which keys of the cloud provider config were
+// set. Only the names of the keys are logged, since the values can hold
+// credentials.
+func logCloudProviderConfigKeys(installConfig *installconfig.InstallConfig, clusterID *installconfig.ClusterID, cm *corev1.ConfigMap) {
+	keys := []string{}
+	if cm != nil {
+		keys = sets.List(sets.KeySet(cm.Data))
+	}
+	logrus.WithFields(logrus.Fields{
+		"platform": installConfig.Config.Platform.Name(),
+		"infraID":  clusterID.InfraID,
+		"keys":     keys,
+	}).Debug("Generated the cloud provider config")
+}
+
+// Preview returns the manifest that Generate would write, without modifying
+// the asset. The returned bool is false when the platform does not use a cloud
+// provider config, in which case no manifest is generated.
+func (cpc *CloudProviderConfig) Preview(ctx context.Context, dependencies asset.Parents) (string, bool, error) {
+	cm, cmData, err := cpc.render(ctx, dependencies)
+	if err != nil {
+		return "", false, err
+	}
+	if cm == nil {
+		return "", false, nil
+	}
+	return string(cmData), true, nil
+}
+
+// render builds the ConfigMap from the parent assets along with its marshaled
+// manifest. Both are nil when the platform does not use a cloud provider config.
+func (cpc *CloudProviderConfig) render(ctx context.Context, dependencies asset.Parents) (*corev1.ConfigMap, []byte, error) {
+	installConfig := &installconfig.InstallConfig{}
+	clusterID := &installconfig.ClusterID{}
+	overrides := &CloudProviderConfigOverrides{}
+	caBundle := &CloudProviderConfigCABundle{}
+	dependencies.Get(installConfig, clusterID, overrides, caBundle)
+	return cpc.renderConfigMap(ctx, installConfig, clusterID, withDataOverrides(overrides.Data), withCABundle(caBundle.Bundle))
+}
+
+// renderConfigMap builds the ConfigMap with the options of the asset after
+// the given ones, along with its marshaled manifest.
+func (cpc *CloudProviderConfig) renderConfigMap(ctx context.Context, installConfig *installconfig.InstallConfig, clusterID *installconfig.ClusterID, opts ...CloudProviderConfigOption) (*corev1.ConfigMap, []byte, error) {
+	opts = append(opts, cpc.options...)
+	cm, err := BuildCloudProviderConfigMap(ctx, installConfig, clusterID, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cm == nil {
+		return nil, nil, nil
+	}
+
+	cmData, err := yaml.Marshal(cm)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to create %s manifest", cpc.Name())
+	}
+	return cm, cmData, nil
+}
+
+// BuildCloudProviderConfigMap builds the cloud-provider-config ConfigMap for the
+// platform in the install config without writing any files. A nil ConfigMap is
+// returned for platforms which do not use a cloud provider config, unless the
+// external cloud providers of a preview feature set are enabled, in which case
+// the ConfigMap has no data.
+func BuildCloudProviderConfigMap(ctx context.Context, installConfig *installconfig.InstallConfig, clusterID *installconfig.ClusterID, opts ...CloudProviderConfigOption) (*corev1.ConfigMap, error) {
+	options := &cloudProviderConfigOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if installConfig == nil || installConfig.Config == nil {
+		return nil, errors.New("install config is missing")
+	}
 
 	cm := &corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -89,255 +503,512 @@ func (cpc *CloudProviderConfig) Generate(ctx context.Context, dependencies asset
 		},
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: "openshift-config",
-			Name:      "cloud-provider-config",
+			Name:      cloudProviderConfigName,
 		},
 		Data: map[string]string{},
 	}
 
-	switch installConfig.Config.Platform.Name() {
-	case externaltypes.Name, nonetypes.Name, baremetaltypes.Name, ovirttypes.Name:
-		return nil
-	case awstypes.Name:
-		// Store the additional trust bundle in the ca-bundle.pem key if the cluster is being installed on a C2S region.
-		trustBundle := installConfig.Config.AdditionalTrustBundle
-		if trustBundle != "" && awstypes.IsSecretRegion(installConfig.Config.AWS.Region) {
-			cm.Data[cloudProviderConfigCABundleDataKey] = trustBundle
-		}
+	// The lookups of the platforms which need them are replaced with
+	// placeholders when offline, which is only supported by some platforms.
+	offline := cloudProviderConfigOffline()
+	usedPlaceholders := false
 
-		// Include a non-empty kube config to appease components--such as the kube-apiserver--that
-		// expect there to be a kube config if the cloud-provider-config ConfigMap exists. See
-		// https://bugzilla.redhat.com/show_bug.cgi?id=1926975.
-		// Note that the newline is required in order to be valid yaml.
-		cm.Data[cloudProviderConfigDataKey] = `[Global]
-`
-	case openstacktypes.Name:
-		cloudProviderConfigData, cloudProviderConfigCABundleData, err := openstackmanifests.GenerateCloudProviderConfig(ctx, *installConfig.Config)
-		if err != nil {
-			return errors.Wrap(err, "failed to generate OpenStack provider config")
+	platformName := installConfig.Config.Platform.Name()
+	if !usesCloudProviderConfigMap(installConfig.Config) {
+		return nil, nil
+	}
+
+	if cloudProviderConfigInfraIDPlatforms.Has(platformName) && (clusterID == nil || clusterID.InfraID == "") {
+		return nil, errors.Errorf("the infrastructure ID of the cluster is required to generate the %s cloud provider config", platformName)
+	}
+
+	generate, ok := cloudProviderConfigGenerators[platformName]
+	switch {
+	case ok:
+		req := &cloudProviderConfigRequest{
+			installConfig: installConfig,
+			clusterID:     clusterID,
+			options:       options,
+			offline:       offline,
 		}
-		cm.Data[cloudProviderConfigDataKey] = cloudProviderConfigData
-		if cloudProviderConfigCABundleData != "" {
-			cm.Data[cloudProviderConfigCABundleDataKey] = cloudProviderConfigCABundleData
+		if err := generate(ctx, req, cm); err != nil {
+			return nil, err
 		}
+		usedPlaceholders = req.usedPlaceholders
+	case platformsWithoutCloudProviderConfig.Has(platformName):
+		// The cloud controller manager operator expects the ConfigMap to
+		// exist once external cloud providers are enabled, even if empty.
+		fillExternalCloudProviderConfig(installConfig.Config, cm)
+	default:
+		supported := sets.List(platformsWithCloudProviderConfig.Union(platformsWithoutCloudProviderConfig))
+		return nil, &UnsupportedPlatformError{Platform: platformName, Supported: supported}
+	}
 
-	case azuretypes.Name:
-		session, err := installConfig.Azure.Session()
-		if err != nil {
-			return errors.Wrap(err, "could not get azure session")
+	// The overrides from disk take precedence over the additions from the
+	// install config.
+	if addition := installConfig.Config.CloudProviderConfigAdditions[platformName]; addition != "" {
+		if err := applyCloudProviderConfigAddition(cm.Data, addition); err != nil {
+			return nil, errors.Wrapf(err, "failed to apply the cloudProviderConfigAdditions of %s", platformName)
 		}
+	}
 
-		nsg := installConfig.Config.Azure.NetworkSecurityGroupName(clusterID.InfraID)
-		nrg := installConfig.Config.Azure.ClusterResourceGroupName(clusterID.InfraID)
-		if installConfig.Config.Azure.NetworkResourceGroupName != "" {
-			nrg = installConfig.Config.Azure.NetworkResourceGroupName
-		}
-		vnet := fmt.Sprintf("%s-vnet", clusterID.InfraID)
-		if installConfig.Config.Azure.VirtualNetwork != "" {
-			vnet = installConfig.Config.Azure.VirtualNetwork
+	if err := applyCloudProviderConfigOverrides(cm.Data, options.dataOverrides); err != nil {
+		return nil, errors.Wrapf(err, "failed to apply %s", cloudProviderConfigOverridesFileName)
+	}
+
+	// A CA bundle provided by the user, e.g. for the private CA of the
+	// endpoints of the AWS isolated regions, takes precedence over the
+	// generated one.
+	if options.caBundle != "" {
+		cm.Data[CABundleDataKey] = options.caBundle
+	}
+
+	warnIfTrustBundleNotInCloudProviderConfig(installConfig, cm)
+
+	if usedPlaceholders {
+		logrus.Warnf("The cloud provider config for %s was generated offline, replace its %s values with the ones of the cloud before installing the cluster", platformName, offlinePlaceholder)
+		metav1.SetMetaDataAnnotation(&cm.ObjectMeta, cloudProviderConfigOfflineAnnotation, "true")
+	}
+
+	// Record non-default feature sets, since the config generated for some
+	// platforms depends on the feature gates they enable.
+	if featureSet := installConfig.Config.FeatureSet; featureSet != configv1.Default {
+		metav1.SetMetaDataAnnotation(&cm.ObjectMeta, cloudProviderConfigFeatureSetAnnotation, string(featureSet))
+	}
+
+	mirrors, err := imageMirrorsAnnotation(installConfig.Config)
+	if err != nil {
+		return nil, err
+	}
+	if mirrors != "" {
+		metav1.SetMetaDataAnnotation(&cm.ObjectMeta, cloudProviderConfigImageMirrorsAnnotation, mirrors)
+	}
+
+	if err := validateCloudProviderConfigFormat(installConfig.Config, cm.Data); err != nil {
+		return nil, err
+	}
+
+	if err := validateAzureResourceGroups(installConfig.Config, cm.Data); err != nil {
+		return nil, err
+	}
+
+	if err := validateCloudProviderConfigDataSize(cm.Data); err != nil {
+		return nil, err
+	}
+
+	if err := validateCloudProviderConfigCABundle(cm.Data); err != nil {
+		return nil, err
+	}
+
+	if err := validateCloudProviderConfigCABundleReference(cm.Data); err != nil {
+		return nil, err
+	}
+
+	metav1.SetMetaDataAnnotation(&cm.ObjectMeta, cloudProviderConfigChecksumAnnotation, cloudProviderConfigChecksum(cm.Data))
+
+	return cm, nil
+}
+
+// cloudProviderConfigChecksum returns the SHA-256 checksum of the data of the
+// ConfigMap. The keys are hashed in sorted order, each key and value prefixed
+// with its length, so that the checksum only depends on the data.
+func cloudProviderConfigChecksum(data map[string]string) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(h, "%d:%s%d:%s", len(k), k, len(data[k]), data[k])
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil))
+}
+
+// imageMirrorsAnnotation returns the image mirrors from the install config as
+// JSON, or an empty string when no mirrors are configured.
+func imageMirrorsAnnotation(ic *types.InstallConfig) (string, error) {
+	sources := append([]types.ImageDigestSource{}, ic.ImageDigestSources...)
+	for _, source := range ic.DeprecatedImageContentSources {
+		sources = append(sources, types.ImageDigestSource{Source: source.Source, Mirrors: source.Mirrors})
+	}
+	if len(sources) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(sources)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the image mirrors")
+	}
+	return string(data), nil
+}
+
+// warnIfTrustBundleNotInCloudProviderConfig lets users know when the
+// additionalTrustBundle from the install config is not passed to the cloud
+// provider, which only consumes it on AWS isolated regions and on GCP with
+// custom service endpoints.
+func warnIfTrustBundleNotInCloudProviderConfig(installConfig *installconfig.InstallConfig, cm *corev1.ConfigMap) {
+	if installConfig.Config.AdditionalTrustBundle == "" || cm.Data[CABundleDataKey] != "" {
+		return
+	}
+	platform := installConfig.Config.Platform.Name()
+	if platform == awstypes.Name {
+		platform = fmt.Sprintf("%s region %s", platform, installConfig.Config.AWS.Region)
+	}
+	logrus.Warnf("The additionalTrustBundle is not added to the cloud provider config for %s, it is only used there on AWS isolated regions, on Azure Stack Hub and on GCP with custom service endpoints. The bundle is still trusted cluster-wide through the user-ca-bundle ConfigMap in the openshift-config namespace.", platform)
+}
+
+// getAzureSession gets the Azure session, retrying transient failures such as
+// the throttling of the authentication. Other failures, e.g. invalid
+// credentials, are returned immediately.
+func getAzureSession(ctx context.Context, getSession func() (*icazure.Session, error)) (*icazure.Session, error) {
+	var (
+		session *icazure.Session
+		lastErr error
+		attempt int
+	)
+	err := wait.ExponentialBackoffWithContext(ctx, azureSessionBackoff, func(context.Context) (bool, error) {
+		attempt++
+		session, lastErr = getSession()
+		if lastErr == nil {
+			return true, nil
 		}
-		subnet := fmt.Sprintf("%s-worker-subnet", clusterID.InfraID)
-		if installConfig.Config.Azure.ComputeSubnet != "" {
-			subnet = installConfig.Config.Azure.ComputeSubnet
+		if !isTransientAzureError(lastErr) {
+			return false, lastErr
 		}
-		azureConfig, err := azure.CloudProviderConfig{
-			CloudName:                installConfig.Config.Azure.CloudName,
-			ResourceGroupName:        installConfig.Config.Azure.ClusterResourceGroupName(clusterID.InfraID),
-			GroupLocation:            installConfig.Config.Azure.Region,
-			ResourcePrefix:           clusterID.InfraID,
-			SubscriptionID:           session.Credentials.SubscriptionID,
-			TenantID:                 session.Credentials.TenantID,
-			NetworkResourceGroupName: nrg,
-			NetworkSecurityGroupName: nsg,
-			VirtualNetworkName:       vnet,
-			SubnetName:               subnet,
-			ResourceManagerEndpoint:  installConfig.Config.Azure.ARMEndpoint,
-			ARO:                      installConfig.Config.Azure.IsARO(),
-		}.JSON()
-		if err != nil {
-			return errors.Wrap(err, "could not create cloud provider config")
+		logrus.Infof("Getting the Azure session failed with a transient error (attempt %d of %d), retrying: %v", attempt, azureSessionRetrySteps, lastErr)
+		return false, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) && lastErr != nil {
+			return nil, errors.Wrapf(lastErr, "failed after %d attempts", attempt)
 		}
-		cm.Data[cloudProviderConfigDataKey] = azureConfig
-
-		if installConfig.Azure.CloudName == azuretypes.StackCloud {
-			b, err := json.Marshal(session.Environment)
-			if err != nil {
-				return errors.Wrap(err, "could not serialize Azure Stack endpoints")
-			}
-			cm.Data[cloudProviderEndpointsKey] = string(b)
+		return nil, err
+	}
+	return session, nil
+}
+
+// isTransientAzureError returns whether the Azure error is caused by
+// throttling or by the service being temporarily unavailable.
+func isTransientAzureError(err error) bool {
+	statusCode := 0
+	var authErr *azidentity.AuthenticationFailedError
+	var respErr *azcore.ResponseError
+	switch {
+	case errors.As(err, &authErr) && authErr.RawResponse != nil:
+		statusCode = authErr.RawResponse.StatusCode
+	case errors.As(err, &respErr):
+		statusCode = respErr.StatusCode
+	}
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// cloudProviderConfigOffline returns whether the cloud provider config is
+// generated without calling the cloud APIs, e.g. to create the manifests in an
+// air-gapped environment.
+func cloudProviderConfigOffline() bool {
+	return os.Getenv("OPENSHIFT_INSTALL_OFFLINE_CLOUD_PROVIDER_CONFIG") == "1"
+}
+
+// ibmcloudAccountIDTimeout returns how long to wait for IAM to return the IBM
+// Cloud account ID, which can be overridden for slow IAM endpoints.
+func ibmcloudAccountIDTimeout() time.Duration {
+	env, ok := os.LookupEnv("OPENSHIFT_INSTALL_IBMCLOUD_ACCOUNT_ID_TIMEOUT")
+	if !ok || env == "" {
+		return defaultIBMCloudAccountIDTimeout
+	}
+	timeout, err := time.ParseDuration(env)
+	if err != nil || timeout <= 0 {
+		logrus.Warnf("Ignoring invalid OPENSHIFT_INSTALL_IBMCLOUD_ACCOUNT_ID_TIMEOUT %q, using the default of %s", env, defaultIBMCloudAccountIDTimeout)
+		return defaultIBMCloudAccountIDTimeout
+	}
+	return timeout
+}
+
+// knownCloudProviderConfigDataKeys are the data keys read from the cloud
+// provider config by the cloud providers of any platform.
+var knownCloudProviderConfigDataKeys = sets.New(ConfigDataKey, CABundleDataKey, EndpointsKey, ProviderDataKey, ExternalConfigDataKey)
+
+// validateCloudProviderConfigDataKeys checks that the data only holds known
+// keys, since the others, e.g. a misspelled config key, are silently ignored.
+func validateCloudProviderConfigDataKeys(data map[string]string) error {
+	var unknown []string
+	for k := range data {
+		if !knownCloudProviderConfigDataKeys.Has(k) {
+			unknown = append(unknown, k)
 		}
-	case gcptypes.Name:
-		subnet := fmt.Sprintf("%s-worker-subnet", clusterID.InfraID)
-		if installConfig.Config.GCP.ComputeSubnet != "" {
-			subnet = installConfig.Config.GCP.ComputeSubnet
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return errors.Errorf("unknown data keys %s in the cloud provider config, the known keys are %s", strings.Join(unknown, ", "), strings.Join(sets.List(knownCloudProviderConfigDataKeys), ", "))
+}
+
+// validateCloudProviderConfigDataSize checks that the combined size of all the
+// data keys fits in a single ConfigMap, mirroring the API server validation.
+func validateCloudProviderConfigDataSize(data map[string]string) error {
+	size := 0
+	for k, v := range data {
+		size += len(k) + len(v)
+	}
+	if size > maxCloudProviderConfigDataSize {
+		return errors.Errorf("cloud provider config data is %d bytes, which exceeds the ConfigMap limit of %d bytes; reduce the size of the additionalTrustBundle by removing certificates that are not needed to reach the cloud endpoints", size, maxCloudProviderConfigDataSize)
+	}
+	return nil
+}
+
+// validateCloudProviderConfigCABundle checks that the CA bundle of the cloud
+// provider config, if any, only holds PEM encoded certificates. A bundle the
+// cloud provider cannot parse only shows later as TLS failures of the cloud
+// controllers.
+func validateCloudProviderConfigCABundle(data map[string]string) error {
+	bundle, ok := data[CABundleDataKey]
+	if !ok {
+		return nil
+	}
+	_, err := parseCloudProviderConfigCABundle(bundle)
+	return err
+}
+
+// cloudProviderConfigCABundlePath is the path the cloud providers read the CA
+// bundle of the cloud provider config from.
+const cloudProviderConfigCABundlePath = "/etc/kubernetes/static-pod-resources/configmaps/cloud-config/" + CABundleDataKey
+
+// validateCloudProviderConfigCABundleReference checks that the config only
+// reads the CA bundle from the ConfigMap when the ConfigMap holds one, since
+// the cloud provider fails to start on a missing or empty CA file.
+func validateCloudProviderConfigCABundleReference(data map[string]string) error {
+	if !strings.Contains(data[ConfigDataKey], cloudProviderConfigCABundlePath) {
+		return nil
+	}
+	if strings.TrimSpace(data[CABundleDataKey]) == "" {
+		return errors.Errorf("the config reads the CA bundle from %s, but the cloud provider config has no %s", cloudProviderConfigCABundlePath, CABundleDataKey)
+	}
+	return nil
+}
+
+// parseCloudProviderConfigCABundle returns the certificates of the CA bundle,
+// failing when any of its PEM blocks is not a certificate.
+func parseCloudProviderConfigCABundle(bundle string) ([]*x509.Certificate, error) {
+	var (
+		blocks       int
+		certificates []*x509.Certificate
+		failures     []string
+	)
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
 		}
-		gcpConfig, err := gcpmanifests.CloudProviderConfig(clusterID.InfraID, installConfig.Config.GCP.ProjectID, subnet, installConfig.Config.GCP.NetworkProjectID)
-		if err != nil {
-			return errors.Wrap(err, "could not create cloud provider config")
+		blocks++
+		if block.Type != "CERTIFICATE" {
+			failures = append(failures, fmt.Sprintf("block %d is a %s, not a CERTIFICATE", blocks, block.Type))
+			continue
 		}
-		cm.Data[cloudProviderConfigDataKey] = gcpConfig
-	case ibmcloudtypes.Name:
-		accountID, err := installConfig.IBMCloud.AccountID(ctx)
+		certificate, err := x509.ParseCertificate(block.Bytes)
 		if err != nil {
-			return err
+			failures = append(failures, fmt.Sprintf("block %d: %v", blocks, err))
+			continue
 		}
+		certificates = append(certificates, certificate)
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		failures = append(failures, "trailing data is not PEM encoded")
+	}
+	if blocks == 0 && len(failures) == 0 {
+		failures = append(failures, "no PEM blocks found")
+	}
+	if len(failures) > 0 {
+		return nil, errors.Errorf("invalid %s in the cloud provider config, parsed %d of %d PEM blocks: %s", CABundleDataKey, len(certificates), blocks, strings.Join(failures, "; "))
+	}
+	return certificates, nil
+}
 
-		subnetNames := []string{}
-		cpSubnets, err := installConfig.IBMCloud.ControlPlaneSubnets(ctx)
-		if err != nil {
-			return errors.Wrap(err, "could not retrieve IBM Cloud control plane subnets")
-		}
-		for _, cpSubnet := range cpSubnets {
-			subnetNames = append(subnetNames, cpSubnet.Name)
-		}
+// Files returns the files generated by the asset.
+func (cpc *CloudProviderConfig) Files() []*asset.File {
+	if cpc.File != nil {
+		return []*asset.File{cpc.File}
+	}
+	return []*asset.File{}
+}
 
-		computeSubnets, err := installConfig.IBMCloud.ComputeSubnets(ctx)
-		if err != nil {
-			return errors.Wrap(err, "could not retrieve IBM Cloud compute subnets")
+// PersistToFile writes the manifest of the cloud provider config in the asset
+// directory. Unlike the default writer of the asset store, the errors name the
+// manifest along with the underlying OS error, e.g. when the manifests
+// directory is on a read-only file system.
+func (cpc *CloudProviderConfig) PersistToFile(directory string) error {
+	for _, f := range cpc.Files() {
+		path := filepath.Join(directory, f.Filename)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return errors.Wrapf(err, "failed to create the directory of %s", f.Filename)
 		}
-		for _, computeSubnet := range computeSubnets {
-			subnetNames = append(subnetNames, computeSubnet.Name)
+		if err := os.WriteFile(path, f.Data, 0o640); err != nil { //nolint:gosec // no sensitive info
+			return errors.Wrapf(err, "failed to write %s", f.Filename)
 		}
+	}
+	return nil
+}
 
-		controlPlane := &ibmcloudtypes.MachinePool{}
-		controlPlane.Set(installConfig.Config.Platform.IBMCloud.DefaultMachinePlatform)
-		controlPlane.Set(installConfig.Config.ControlPlane.Platform.IBMCloud)
-		compute := &ibmcloudtypes.MachinePool{}
-		compute.Set(installConfig.Config.Platform.IBMCloud.DefaultMachinePlatform)
-		compute.Set(installConfig.Config.WorkerMachinePool().Platform.IBMCloud)
-
-		if len(controlPlane.Zones) == 0 || len(compute.Zones) == 0 {
-			zones, err := ibmcloudmachines.AvailabilityZones(installConfig.Config.IBMCloud.Region, installConfig.Config.Platform.IBMCloud.ServiceEndpoints)
-			if err != nil {
-				return errors.Wrapf(err, "could not get availability zones for %s", installConfig.Config.IBMCloud.Region)
-			}
-			if len(controlPlane.Zones) == 0 {
-				controlPlane.Zones = zones
-			}
-			if len(compute.Zones) == 0 {
-				compute.Zones = zones
-			}
-		}
+// WriteTo writes the manifest of the generated or loaded cloud provider config
+// to w, e.g. to stream it into an archive without going through the files of
+// the asset. It fails when there is no cloud provider config.
+func (cpc *CloudProviderConfig) WriteTo(w io.Writer) (int64, error) {
+	if cpc.NotApplicable {
+		return 0, errors.New("the cloud provider config is not used on this platform")
+	}
+	if cpc.ConfigMap == nil {
+		return 0, errors.New("the cloud provider config has not been generated")
+	}
+	data, err := yaml.Marshal(cpc.ConfigMap)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create %s manifest", cpc.Name())
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
 
-		ibmcloudConfig, err := ibmcloudmanifests.CloudProviderConfig(
-			clusterID.InfraID,
-			accountID,
-			installConfig.Config.IBMCloud.Region,
-			installConfig.Config.Platform.IBMCloud.ClusterResourceGroupName(clusterID.InfraID),
-			installConfig.Config.Platform.IBMCloud.GetVPCName(),
-			subnetNames,
-			controlPlane.Zones,
-			compute.Zones,
-			installConfig.Config.Platform.IBMCloud.ServiceEndpoints,
-		)
-		if err != nil {
-			return errors.Wrap(err, "could not create cloud provider config")
-		}
-		cm.Data[cloudProviderConfigDataKey] = ibmcloudConfig
-	case powervstypes.Name:
-		var (
-			accountID, vpcRegion string
-			err                  error
-		)
-
-		if accountID, err = installConfig.PowerVS.AccountID(ctx); err != nil {
-			return err
-		}
+// UpdateCABundle replaces the CA bundle of the generated or loaded cloud
+// provider config, e.g. when rotating the certificates of an isolated region,
+// without generating the rest of the config again. It fails when the cloud
+// provider config does not hold a CA bundle, since the cloud provider of the
+// platform or region does not read it then.
+func (cpc *CloudProviderConfig) UpdateCABundle(trustBundle string) error {
+	if cpc.NotApplicable {
+		return errors.New("the cloud provider config is not used on this platform")
+	}
+	if cpc.ConfigMap == nil {
+		return errors.New("the cloud provider config has not been generated")
+	}
+	if _, ok := cpc.ConfigMap.Data[CABundleDataKey]; !ok {
+		return errors.Errorf("the cloud provider config has no %s, the CA bundle is not used on this platform or region", CABundleDataKey)
+	}
 
-		vpcRegion = installConfig.Config.PowerVS.VPCRegion
-		if vpcRegion == "" {
-			vpcRegion, err = powervstypes.VPCRegionForPowerVSRegion(installConfig.Config.PowerVS.Region)
-		}
-		if err != nil {
-			return err
-		}
+	cm := cpc.ConfigMap.DeepCopy()
+	cm.Data[CABundleDataKey] = trustBundle
+	metav1.SetMetaDataAnnotation(&cm.ObjectMeta, cloudProviderConfigChecksumAnnotation, cloudProviderConfigChecksum(cm.Data))
 
-		vpc := installConfig.Config.PowerVS.VPCName
-		vpcSubnets := installConfig.Config.PowerVS.VPCSubnets
-		if vpc == "" {
-			vpc = fmt.Sprintf("vpc-%s", clusterID.InfraID)
-		} else {
-			existingSubnets, err := installConfig.PowerVS.GetVPCSubnets(ctx, vpc)
-			if err != nil {
-				return err
-			}
-
-			// cluster-api-provider-ibm requires any existing VPC subnet to be specified in the cluster
-			// manifest and as such we need to also specify these in the cloudproviderconfig.
-			// @TODO: Deprecate platform.powervs.vpcSubnets?
-			for _, subnet := range existingSubnets {
-				vpcSubnets = append(vpcSubnets, *subnet.Name)
-			}
-		}
+	fileName := cpc.fileName()
+	if cpc.File != nil {
+		fileName = cpc.File.Filename
+	}
+	if err := cpc.consolidate(cm, fileName); err != nil {
+		return err
+	}
+	// The updated CA bundle is not generated from the parent assets, so the
+	// next generation must not keep it.
+	cpc.generatedFrom = ""
+	return nil
+}
 
-		if len(vpcSubnets) == 0 {
-			if capiutils.IsEnabled(installConfig) {
-				vpcZones, err := powervstypes.AvailableVPCZones(installConfig.Config.PowerVS.Region)
-				if err != nil {
-					return err
-				}
-
-				// The PowerVS CAPI provider generates three subnets.  One for
-				// each of the endpoint.
-				// @TODO the provider should export a function which gives us
-				// an array
-				for _, zone := range vpcZones {
-					vpcSubnets = append(vpcSubnets,
-						fmt.Sprintf("%s-vpcsubnet-%s", clusterID.InfraID, zone))
-				}
-			} else {
-				vpcSubnets = append(vpcSubnets, fmt.Sprintf("vpc-subnet-%s", clusterID.InfraID))
-			}
-		}
+// Certificates returns the certificates of the CA bundle of the generated or
+// loaded cloud provider config, e.g. to report the CAs which expire soon. It
+// returns no certificates when the cloud provider of the platform or region
+// does not read a CA bundle.
+func (cpc *CloudProviderConfig) Certificates() ([]*x509.Certificate, error) {
+	if cpc.NotApplicable {
+		return []*x509.Certificate{}, nil
+	}
+	if cpc.ConfigMap == nil {
+		return nil, errors.New("the cloud provider config has not been generated")
+	}
+	bundle, ok := cpc.ConfigMap.Data[CABundleDataKey]
+	if !ok {
+		return []*x509.Certificate{}, nil
+	}
+	return parseCloudProviderConfigCABundle(bundle)
+}
 
-		var (
-			serviceGUID string
-			serviceName string
-		)
+// Reset clears the generated or loaded cloud provider config, so that the
+// asset can be generated again. The options of the asset are kept.
+func (cpc *CloudProviderConfig) Reset() {
+	cpc.ConfigMap = nil
+	cpc.File = nil
+	cpc.NotApplicable = false
+	cpc.generatedFrom = ""
+}
 
-		if installConfig.Config.PowerVS.ServiceInstanceGUID == "" {
-			serviceName = fmt.Sprintf("%s-power-iaas", clusterID.InfraID)
-		} else {
-			serviceGUID = installConfig.Config.PowerVS.ServiceInstanceGUID
+// DeepCopy returns a copy of the asset which shares no mutable state with it,
+// so that it can be kept as a snapshot while the asset is modified, e.g. by
+// UpdateCABundle.
+func (cpc *CloudProviderConfig) DeepCopy() *CloudProviderConfig {
+	if cpc == nil {
+		return nil
+	}
+	out := *cpc
+	out.ConfigMap = cpc.ConfigMap.DeepCopy()
+	if cpc.File != nil {
+		out.File = &asset.File{
+			Filename: cpc.File.Filename,
+			Data:     append([]byte(nil), cpc.File.Data...),
 		}
+	}
+	out.options = append([]CloudProviderConfigOption(nil), cpc.options...)
+	return &out
+}
 
-		powervsConfig, err := powervsmanifests.CloudProviderConfig(
-			clusterID.InfraID,
-			accountID,
-			vpc,
-			vpcRegion,
-			installConfig.Config.Platform.PowerVS.PowerVSResourceGroup,
-			vpcSubnets,
-			serviceGUID,
-			serviceName,
-			installConfig.Config.PowerVS.Region,
-			installConfig.Config.PowerVS.Zone,
-		)
-		if err != nil {
-			return errors.Wrap(err, "could not create cloud provider config")
-		}
-		cm.Data[cloudProviderConfigDataKey] = powervsConfig
-	case vspheretypes.Name:
-		var vsphereConfig string
-		var err error
-		// When we GA multi vcenter, we should only support yaml generation here.
-		if installConfig.Config.EnabledFeatureGates().Enabled(features.FeatureGateVSphereMultiVCenters) {
-			vsphereConfig, err = vspheremanifests.CloudProviderConfigYaml(clusterID.InfraID, installConfig.Config.Platform.VSphere)
-		} else {
-			vsphereConfig, err = vspheremanifests.CloudProviderConfigIni(clusterID.InfraID, installConfig.Config.Platform.VSphere)
+// Load loads the already-rendered files back from disk. The cloud provider
+// config in the manifests directory is loaded as part of the manifests, so it
+// is only loaded from a directory set with WithManifestDir. A loaded cloud
+// provider config is always applicable. A manifest which only holds a CA
+// bundle is not loaded, the CloudProviderConfigCABundle asset reads it from
+// the manifests directory instead.
+func (cpc *CloudProviderConfig) Load(f asset.FileFetcher) (bool, error) {
+	options := cpc.resolveOptions()
+	if options.manifestDir == "" {
+		return false, nil
+	}
+
+	fileName := cpc.fileName()
+	file, err := f.FetchByName(fileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
 		}
+		return false, errors.Wrapf(err, "failed to load %s", fileName)
+	}
 
-		if err != nil {
-			return errors.Wrap(err, "could not create cloud provider config")
+	cm := &corev1.ConfigMap{}
+	if err := yaml.Unmarshal(file.Data, cm); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", fileName)
+	}
+	if err := validateCloudProviderConfigDataKeys(cm.Data); err != nil {
+		if options.strictLoad {
+			return false, errors.Wrapf(err, "failed to validate %s", fileName)
 		}
-		cm.Data[cloudProviderConfigDataKey] = vsphereConfig
-	case nutanixtypes.Name:
-		configJSON, err := nutanixmanifests.CloudConfigJSON(installConfig.Config.Nutanix)
-		if err != nil {
-			return errors.Wrap(err, "could not create Nutanix Cloud provider config")
+		logrus.Warnf("The keys are ignored by the cloud provider, check %s for typos: %v", fileName, err)
+	}
+	if _, ok := cm.Data[CABundleDataKey]; ok && len(cm.Data) == 1 {
+		if err := validateCloudProviderConfigCABundle(cm.Data); err != nil {
+			return false, errors.Wrapf(err, "failed to validate %s", fileName)
 		}
-		cm.Data[cloudProviderConfigDataKey] = configJSON
-	default:
-		return errors.New("invalid Platform")
+		return false, nil
+	}
+	if checksum, ok := cm.Annotations[cloudProviderConfigChecksumAnnotation]; ok && checksum != cloudProviderConfigChecksum(cm.Data) {
+		logrus.Warnf("The data of %s does not match its %s annotation, the cloud provider config was edited after it was generated", fileName, cloudProviderConfigChecksumAnnotation)
+	}
+	if err := cpc.consolidate(cm, fileName); err != nil {
+		return false, errors.Wrapf(err, "failed to validate %s", fileName)
+	}
+	cpc.NotApplicable = false
+	cpc.generatedFrom = ""
+	return true, nil
+}
+
+// consolidate validates the data of the ConfigMap as a whole once it is
+// mutated, so that the config never reads a CA bundle the ConfigMap lacks, and
+// sets the ConfigMap and its manifest, marshaled again, on the asset. The asset is left unchanged when the data is invalid.
+func (cpc *CloudProviderConfig) consolidate(cm *corev1.ConfigMap, fileName string) error {
+	if err := validateCloudProviderConfigCABundle(cm.Data); err != nil {
+		return err
+	}
+	if err := validateCloudProviderConfigCABundleReference(cm.Data); err != nil {
+		return err
+	}
+	if err := validateCloudProviderConfigDataSize(cm.Data); err != nil {
+		return err
 	}
 
 	cmData, err := yaml.Marshal(cm)
@@ -346,21 +1017,24 @@ func (cpc *CloudProviderConfig) Generate(ctx context.Context, dependencies asset
 	}
 	cpc.ConfigMap = cm
 	cpc.File = &asset.File{
-		Filename: cloudProviderConfigFileName,
+		Filename: fileName,
 		Data:     cmData,
 	}
 	return nil
 }
 
-// Files returns the files generated by the asset.
-func (cpc *CloudProviderConfig) Files() []*asset.File {
-	if cpc.File != nil {
-		return []*asset.File{cpc.File}
+// fileName returns the path of the cloud provider config manifest.
+func (cpc *CloudProviderConfig) fileName() string {
+	if dir := cpc.resolveOptions().manifestDir; dir != "" {
+		return filepath.Join(dir, filepath.Base(cloudProviderConfigFileName))
 	}
-	return []*asset.File{}
+	return cloudProviderConfigFileName
 }
 
-// Load loads the already-rendered files back from disk.
-func (cpc *CloudProviderConfig) Load(f asset.FileFetcher) (bool, error) {
-	return false, nil
+func (cpc *CloudProviderConfig) resolveOptions() *cloudProviderConfigOptions {
+	options := &cloudProviderConfigOptions{}
+	for _, opt := range cpc.options {
+		opt(options)
+	}
+	return options
 }
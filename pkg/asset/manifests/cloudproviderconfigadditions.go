@@ -0,0 +1,171 @@
+package manifests
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	ini "gopkg.in/ini.v1"
+	"sigs.k8s.io/yaml"
+)
+
+// applyCloudProviderConfigAddition merges the addition from the install config
+// into the generated cloud provider config, in the format of the generated
+// config. The keys of an INI addition replace the ones of the same sections,
+// which are created when missing, while a JSON or YAML addition is merged
+// field by field.
+func applyCloudProviderConfigAddition(data map[string]string, addition string) error {
+	config, ok := data[ConfigDataKey]
+	if !ok {
+		return errors.New("no cloud provider config is generated to add to")
+	}
+
+	var merged string
+	var err error
+	switch trimmed := strings.TrimSpace(config); {
+	case strings.HasPrefix(trimmed, "{"):
+		merged, err = mergeJSONConfig(config, addition)
+	case strings.HasPrefix(trimmed, "["):
+		merged, err = mergeINIConfig(config, addition)
+	default:
+		merged, err = mergeYAMLConfig(config, addition)
+	}
+	if err != nil {
+		return err
+	}
+	data[ConfigDataKey] = merged
+	return nil
+}
+
+func mergeJSONConfig(config, addition string) (string, error) {
+	configObject, ok := parseJSONObject(config)
+	if !ok {
+		return "", errors.New("the generated config is not a JSON object")
+	}
+	additionObject, ok := parseJSONObject(addition)
+	if !ok {
+		return "", errors.New("the addition is not a JSON object")
+	}
+	merged, err := encodeJSONConfig(mergeJSONObjects(configObject, additionObject))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode the merged config")
+	}
+	return merged, nil
+}
+
+func mergeYAMLConfig(config, addition string) (string, error) {
+	configObject := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(config), &configObject); err != nil {
+		return "", errors.Wrap(err, "failed to parse the generated config")
+	}
+	additionObject := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(addition), &additionObject); err != nil {
+		return "", errors.Wrap(err, "failed to parse the addition")
+	}
+	merged, err := yaml.Marshal(mergeJSONObjects(configObject, additionObject))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode the merged config")
+	}
+	return string(merged), nil
+}
+
+// mergeINIConfig sets the keys of the addition in the generated config line by
+// line, so that the formatting of the rest of the generated config is kept.
+// The merged config is parsed again, since the lines of the addition could
+// make it invalid.
+func mergeINIConfig(config, addition string) (string, error) {
+	// Keys can be repeated in the cloud provider configs, e.g., node-tags for GCP.
+	loadOptions := ini.LoadOptions{AllowShadows: true}
+	additionFile, err := ini.LoadSources(loadOptions, []byte(addition))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse the addition")
+	}
+
+	lines := strings.Split(strings.TrimRight(config, "\n"), "\n")
+	for _, section := range additionFile.Sections() {
+		if section.Name() == ini.DefaultSection {
+			if len(section.Keys()) > 0 {
+				return "", errors.New("the keys of the addition must be in a section")
+			}
+			continue
+		}
+		if iniSectionStart(lines, section.Name()) < 0 {
+			lines = append(lines, "", fmt.Sprintf("[%s]", section.Name()))
+		}
+		for _, key := range section.Keys() {
+			lines = setINIKey(lines, section.Name(), key.Name(), key.ValueWithShadows())
+		}
+	}
+
+	merged := strings.Join(lines, "\n") + "\n"
+	if _, err := ini.LoadSources(loadOptions, []byte(merged)); err != nil {
+		return "", errors.Wrap(err, "failed to parse the merged config")
+	}
+	return merged, nil
+}
+
+// setINIKey replaces the lines of the key in the section with the given
+// values. New keys are added after the last key of the section.
+func setINIKey(lines []string, section, key string, values []string) []string {
+	start := iniSectionStart(lines, section)
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "[") {
+			end = i
+			break
+		}
+	}
+
+	kept := make([]string, 0, end-start)
+	insert := -1
+	for _, line := range lines[start+1 : end] {
+		if iniKeyName(line) == key {
+			if insert < 0 {
+				insert = len(kept)
+			}
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if insert < 0 {
+		insert = len(kept)
+		for insert > 0 && strings.TrimSpace(kept[insert-1]) == "" {
+			insert--
+		}
+	}
+
+	result := make([]string, 0, len(lines)+len(values))
+	result = append(result, lines[:start+1]...)
+	result = append(result, kept[:insert]...)
+	for _, value := range values {
+		result = append(result, fmt.Sprintf("%s = %s", key, value))
+	}
+	result = append(result, kept[insert:]...)
+	return append(result, lines[end:]...)
+}
+
+// iniSectionStart returns the index of the header line of the section, or -1
+// when the section is missing.
+func iniSectionStart(lines []string, section string) int {
+	header := fmt.Sprintf("[%s]", section)
+	for i, line := range lines {
+		if strings.TrimSpace(line) == header {
+			return i
+		}
+	}
+	return -1
+}
+
+// iniKeyName returns the name of the key set by the line, or an empty string
+// for blank, comment and section lines.
+func iniKeyName(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "[") {
+		return ""
+	}
+	i := strings.IndexAny(trimmed, "=:")
+	if i < 0 {
+		return trimmed
+	}
+	return strings.TrimSpace(trimmed[:i])
+}
@@ -0,0 +1,143 @@
+package manifests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/types"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+)
+
+func TestApplyCloudProviderConfigAddition(t *testing.T) {
+	cases := []struct {
+		name           string
+		config         string
+		addition       string
+		expectedError  string
+		expectedConfig string
+	}{{
+		name:     "ini key added",
+		config:   "[Global]\nsecret-name = vsphere-creds\n\n[Workspace]\nserver = vcenter\n",
+		addition: "[Global]\ninsecure-flag = 1\n",
+		expectedConfig: `[Global]
+secret-name = vsphere-creds
+insecure-flag = 1
+
+[Workspace]
+server = vcenter
+`,
+	}, {
+		name:     "ini key replaced",
+		config:   "[global]\nproject-id = a\nnode-tags = a-master\nnode-tags = a-worker\nregional = true\n",
+		addition: "[global]\nnode-tags = b-master\n",
+		expectedConfig: `[global]
+project-id = a
+node-tags = b-master
+regional = true
+`,
+	}, {
+		name:     "ini section added",
+		config:   "[Global]\nregion = us-east-1\n",
+		addition: "[ServiceOverride \"0\"]\nService = ec2\nURL = https://ec2.example.com\n",
+		expectedConfig: `[Global]
+region = us-east-1
+
+[ServiceOverride "0"]
+Service = ec2
+URL = https://ec2.example.com
+`,
+	}, {
+		name:          "ini key outside of a section",
+		config:        "[Global]\n",
+		addition:      "region = us-east-1\n",
+		expectedError: `^the keys of the addition must be in a section$`,
+	}, {
+		name:     "json merged",
+		config:   `{"cloud": "AzurePublicCloud", "loadBalancerSku": "standard", "tags": {"a": "1"}}`,
+		addition: `{"loadBalancerSku": "basic", "tags": {"b": "2"}}`,
+		expectedConfig: `{
+	"cloud": "AzurePublicCloud",
+	"loadBalancerSku": "basic",
+	"tags": {
+		"a": "1",
+		"b": "2"
+	}
+}
+`,
+	}, {
+		name:          "json addition not an object",
+		config:        `{"cloud": "AzurePublicCloud"}`,
+		addition:      "[Global]\n",
+		expectedError: `^the addition is not a JSON object$`,
+	}, {
+		name:     "yaml merged",
+		config:   "global:\n  secretName: vsphere-creds\n  insecureFlag: true\n",
+		addition: "global:\n  insecureFlag: false\n",
+		expectedConfig: `global:
+  insecureFlag: false
+  secretName: vsphere-creds
+`,
+	}, {
+		name:          "yaml addition not a mapping",
+		config:        "global:\n  secretName: vsphere-creds\n",
+		addition:      "- insecureFlag\n",
+		expectedError: `^failed to parse the addition: `,
+	}, {
+		name:          "no config",
+		addition:      "[Global]\n",
+		expectedError: `^no cloud provider config is generated to add to$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := map[string]string{}
+			if tc.config != "" {
+				data[ConfigDataKey] = tc.config
+			}
+			err := applyCloudProviderConfigAddition(data, tc.addition)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedConfig, data[ConfigDataKey])
+			}
+		})
+	}
+}
+
+func TestCloudProviderConfigGenerateWithAdditions(t *testing.T) {
+	installConfig := icBuild.build(icBuild.forAzure(), func(ic *types.InstallConfig) {
+		ic.Platform.Azure.CloudName = azuretypes.PublicCloud
+		ic.Platform.Azure.Region = "eastus"
+		ic.CloudProviderConfigAdditions = map[string]string{
+			azuretypes.Name: `{"cloudProviderRateLimit": true, "loadBalancerSku": "basic"}`,
+		}
+	})
+	parents := asset.Parents{}
+	parents.Add(
+		&installconfig.ClusterID{
+			UUID:    "test-uuid",
+			InfraID: "test-infra-id",
+		},
+		installconfig.MakeAsset(installConfig),
+		&CloudProviderConfigOverrides{
+			Data: map[string]interface{}{
+				"config": map[string]interface{}{"loadBalancerSku": "standard"},
+			},
+		},
+		&CloudProviderConfigCABundle{},
+	)
+
+	cpc := NewCloudProviderConfig(WithAzureSession(testAzureSession))
+	if !assert.NoError(t, cpc.Generate(context.Background(), parents), "failed to generate asset") {
+		return
+	}
+	config := cpc.ConfigMap.Data[ConfigDataKey]
+	assert.Contains(t, config, `"cloudProviderRateLimit": true`)
+	assert.Contains(t, config, `"loadBalancerSku": "standard"`)
+	assert.Contains(t, config, `"resourceGroup": "test-infra-id-rg"`)
+}
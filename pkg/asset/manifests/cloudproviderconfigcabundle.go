@@ -0,0 +1,81 @@
+package manifests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// CloudProviderConfigCABundle holds the CA bundle of a cloud provider config
+// manifest which the user placed in the manifests directory with only a
+// ca-bundle.pem key. The bundle is kept over the generated one when the
+// cloud provider config is generated, e.g. to set the CA of a private cloud
+// endpoint ahead of generating the manifests.
+type CloudProviderConfigCABundle struct {
+	File   *asset.File
+	Bundle string
+}
+
+var _ asset.WritableAsset = (*CloudProviderConfigCABundle)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*CloudProviderConfigCABundle) Name() string {
+	return "Cloud Provider Config CA Bundle"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*CloudProviderConfigCABundle) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates no CA bundle, it can only be provided on disk.
+func (b *CloudProviderConfigCABundle) Generate(_ context.Context, _ asset.Parents) error {
+	return nil
+}
+
+// Files returns no files, so that the manifest provided by the user is not
+// removed from disk before the generated cloud provider config replaces it.
+func (b *CloudProviderConfigCABundle) Files() []*asset.File {
+	return []*asset.File{}
+}
+
+// Load returns the CA bundle of the cloud provider config manifest in the
+// manifests directory when it only holds a CA bundle. A missing manifest, or
+// one holding a config, is not an error.
+func (b *CloudProviderConfigCABundle) Load(f asset.FileFetcher) (bool, error) {
+	for _, ext := range []string{".yaml", ".json"} {
+		fileName := filepath.Join(manifestDir, cloudProviderConfigName+ext)
+		file, err := f.FetchByName(fileName)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return false, errors.Wrapf(err, "failed to load %s", fileName)
+		}
+
+		cm := &corev1.ConfigMap{}
+		// JSON is valid YAML, so manifests in either format are unmarshaled alike.
+		if err := yaml.Unmarshal(file.Data, cm); err != nil {
+			return false, errors.Wrapf(err, "failed to unmarshal %s", fileName)
+		}
+		bundle, ok := cm.Data[CABundleDataKey]
+		if !ok || len(cm.Data) != 1 {
+			return false, nil
+		}
+		if err := validateCloudProviderConfigCABundle(cm.Data); err != nil {
+			return false, errors.Wrapf(err, "failed to validate %s", fileName)
+		}
+		logrus.Infof("Using the %s of %s in the generated cloud provider config", CABundleDataKey, fileName)
+		b.File, b.Bundle = file, bundle
+		return true, nil
+	}
+	return false, nil
+}
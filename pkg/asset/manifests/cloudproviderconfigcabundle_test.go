@@ -0,0 +1,170 @@
+package manifests
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/mock"
+	"github.com/openshift/installer/pkg/asset/store"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const seededCABundle = `-----BEGIN CERTIFICATE-----
+MIIBfjCCASWgAwIBAgIUbED/30mRRBsFOfFAfGucwy+db+EwCgYIKoZIzj0EAwIw
+FDESMBAGA1UEAwwJc2VlZGVkLWNhMCAXDTI2MTAxNTAwMTM0MFoYDzIxMjYwOTIx
+MDAxMzQwWjAUMRIwEAYDVQQDDAlzZWVkZWQtY2EwWTATBgcqhkjOPQIBBggqhkjO
+PQMBBwNCAAQT+gWHzYU/r5ZldR2LRQNHA2B5SmWQnwyl6j0JPei00V7L2n+7Dvn2
+ORxwJjKScV8QiQWEn396xlTeiUiNjpabo1MwUTAdBgNVHQ4EFgQUdEWXjH+EZ/LC
+/eZVNIPs5dw+uAkwHwYDVR0jBBgwFoAUdEWXjH+EZ/LC/eZVNIPs5dw+uAkwDwYD
+VR0TAQH/BAUwAwEB/zAKBggqhkjOPQQDAgNHADBEAiB74X7opUWeqtR8avWlKTIE
+aoUpSvE7QjyWcSqmJkpmzwIgYQb3H30PZm5hsNjnQUs4e20R8+5KNjI15IEg/JBw
+CNs=
+-----END CERTIFICATE-----
+`
+
+// seededCABundleManifest is a cloud provider config manifest which only holds
+// the seeded CA bundle.
+var seededCABundleManifest = "apiVersion: v1\nkind: ConfigMap\ndata:\n  ca-bundle.pem: |\n    " + strings.ReplaceAll(strings.TrimSpace(seededCABundle), "\n", "\n    ") + "\n"
+
+func TestCloudProviderConfigCABundleLoad(t *testing.T) {
+	cases := []struct {
+		name           string
+		data           string
+		expectedFound  bool
+		expectedBundle string
+		expectedError  string
+	}{{
+		name:           "CA bundle only",
+		data:           seededCABundleManifest,
+		expectedFound:  true,
+		expectedBundle: seededCABundle,
+	}, {
+		name: "config",
+		data: "apiVersion: v1\nkind: ConfigMap\ndata:\n  config: |\n    [Global]\n",
+	}, {
+		name:          "invalid CA bundle",
+		data:          "apiVersion: v1\nkind: ConfigMap\ndata:\n  ca-bundle.pem: corrupted\n",
+		expectedError: `^failed to validate manifests/cloud-provider-config.yaml: invalid ca-bundle.pem in the cloud provider config, parsed 0 of 0 PEM blocks: trailing data is not PEM encoded$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+			fileFetcher.EXPECT().FetchByName("manifests/cloud-provider-config.yaml").Return(
+				&asset.File{
+					Filename: "manifests/cloud-provider-config.yaml",
+					Data:     []byte(tc.data),
+				},
+				nil,
+			)
+
+			caBundle := &CloudProviderConfigCABundle{}
+			found, err := caBundle.Load(fileFetcher)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expectedFound, found)
+			assert.Equal(t, tc.expectedBundle, caBundle.Bundle)
+		})
+	}
+}
+
+func TestCloudProviderConfigGenerateSeededCABundle(t *testing.T) {
+	cases := []struct {
+		name          string
+		installConfig *types.InstallConfig
+	}{{
+		name:          "generated CA bundle",
+		installConfig: icBuild.build(icBuild.withAWSRegion("us-iso-east-1"), icBuild.withAdditionalTrustBundle(testTrustBundle)),
+	}, {
+		name:          "no generated CA bundle",
+		installConfig: icBuild.build(icBuild.withAWSRegion("us-east-1")),
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cpc, parents := newTestCloudProviderConfig(tc.installConfig, nil)
+			parents.Add(&CloudProviderConfigCABundle{Bundle: seededCABundle})
+			if !assert.NoError(t, cpc.Generate(context.Background(), parents), "failed to generate asset") {
+				return
+			}
+			assert.Equal(t, "[Global]\n", cpc.ConfigMap.Data[ConfigDataKey])
+			assert.Equal(t, seededCABundle, cpc.ConfigMap.Data[CABundleDataKey], "the seeded CA bundle should take precedence")
+		})
+	}
+}
+
+// TestCloudProviderConfigCABundleFetch checks that a CA bundle placed in the
+// manifests directory reaches the cloud provider config written along with
+// the Infrastructure manifest when the assets are fetched from the store.
+func TestCloudProviderConfigCABundleFetch(t *testing.T) {
+	dir := t.TempDir()
+	installConfig := installconfig.MakeAsset(icBuild.build(icBuild.forVSphere(), func(ic *types.InstallConfig) {
+		ic.Networking = &types.Networking{}
+	}))
+	clusterID := &installconfig.ClusterID{UUID: "test-uuid", InfraID: "test-infra-id"}
+	state, err := json.Marshal(map[string]interface{}{
+		reflect.TypeOf(installConfig).String(): installConfig,
+		reflect.TypeOf(clusterID).String():     clusterID,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, ".openshift_install_state.json"), state, 0o600)) {
+		return
+	}
+	if !assert.NoError(t, os.MkdirAll(filepath.Join(dir, manifestDir), 0o755)) {
+		return
+	}
+	if !assert.NoError(t, os.WriteFile(filepath.Join(dir, manifestDir, "cloud-provider-config.yaml"), []byte(seededCABundleManifest), 0o600)) {
+		return
+	}
+
+	assetStore, err := store.NewStore(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	infra := &Infrastructure{}
+	if !assert.NoError(t, assetStore.Fetch(context.Background(), infra), "failed to fetch asset") {
+		return
+	}
+
+	var cloudProviderConfigFile *asset.File
+	for _, f := range infra.FileList {
+		if f.Filename == filepath.Join(manifestDir, "cloud-provider-config.yaml") {
+			cloudProviderConfigFile = f
+		}
+	}
+	if !assert.NotNil(t, cloudProviderConfigFile, "expected a cloud provider config manifest") {
+		return
+	}
+	cm := &corev1.ConfigMap{}
+	if !assert.NoError(t, yaml.Unmarshal(cloudProviderConfigFile.Data, cm)) {
+		return
+	}
+	assert.Contains(t, cm.Data, ConfigDataKey)
+	assert.Equal(t, seededCABundle, cm.Data[CABundleDataKey])
+
+	var infraConfig configv1.Infrastructure
+	if !assert.NoError(t, yaml.Unmarshal(infra.FileList[len(infra.FileList)-1].Data, &infraConfig)) {
+		return
+	}
+	assert.Equal(t, "cloud-provider-config", infraConfig.Spec.CloudConfig.Name)
+}
@@ -0,0 +1,244 @@
+package manifests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	ini "gopkg.in/ini.v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// Equal returns whether both assets hold the same cloud provider config. The
+// data of the ConfigMaps is parsed before being compared, so differences in
+// whitespace or key order are ignored.
+func (cpc *CloudProviderConfig) Equal(other *CloudProviderConfig) bool {
+	return len(cpc.diff(other)) == 0
+}
+
+// Diff returns a human-readable description of the changes from the cloud
+// provider config of cpc to the one of other, with one change per line. It is
+// empty when both configs are equal.
+func (cpc *CloudProviderConfig) Diff(other *CloudProviderConfig) string {
+	return strings.Join(cpc.diff(other), "\n")
+}
+
+// ChangedFiles returns the files of the asset which differ from the ones
+// fetched from f, so that unchanged manifests are not rewritten. Files missing
+// from f are always returned. The files are compared with Diff, so changes
+// only in formatting are not reported.
+func (cpc *CloudProviderConfig) ChangedFiles(f asset.FileFetcher) ([]*asset.File, error) {
+	changed := []*asset.File{}
+	for _, file := range cpc.Files() {
+		existing, err := f.FetchByName(file.Filename)
+		if err != nil {
+			if os.IsNotExist(err) {
+				changed = append(changed, file)
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to load %s", file.Filename)
+		}
+
+		cm := &corev1.ConfigMap{}
+		if err := yaml.Unmarshal(existing.Data, cm); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", file.Filename)
+		}
+		if !(&CloudProviderConfig{ConfigMap: cm}).Equal(cpc) {
+			changed = append(changed, file)
+		}
+	}
+	return changed, nil
+}
+
+func (cpc *CloudProviderConfig) diff(other *CloudProviderConfig) []string {
+	oldData, newData := cpc.configData(), other.configData()
+	if oldData == nil && newData == nil {
+		return nil
+	}
+	if oldData == nil {
+		return []string{"cloud provider config added"}
+	}
+	if newData == nil {
+		return []string{"cloud provider config removed"}
+	}
+
+	keys := map[string]struct{}{}
+	for k := range oldData {
+		keys[k] = struct{}{}
+	}
+	for k := range newData {
+		keys[k] = struct{}{}
+	}
+
+	var changes []string
+	for _, k := range sortedKeys(keys) {
+		oldValue, inOld := oldData[k]
+		newValue, inNew := newData[k]
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("%s: added", k))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("%s: removed", k))
+		default:
+			for _, change := range diffConfigValue(oldValue, newValue) {
+				changes = append(changes, fmt.Sprintf("%s: %s", k, change))
+			}
+		}
+	}
+	return changes
+}
+
+func (cpc *CloudProviderConfig) configData() map[string]string {
+	if cpc == nil || cpc.ConfigMap == nil {
+		return nil
+	}
+	return cpc.ConfigMap.Data
+}
+
+// diffConfigValue compares two values of the ConfigMap data. Values in a
+// structured format are compared field by field, others as a whole.
+func diffConfigValue(oldValue, newValue string) []string {
+	oldFields, oldOK := parseConfigValue(oldValue)
+	newFields, newOK := parseConfigValue(newValue)
+	if !oldOK || !newOK {
+		if strings.TrimSpace(oldValue) == strings.TrimSpace(newValue) {
+			return nil
+		}
+		return []string{"changed"}
+	}
+
+	keys := map[string]struct{}{}
+	for k := range oldFields {
+		keys[k] = struct{}{}
+	}
+	for k := range newFields {
+		keys[k] = struct{}{}
+	}
+
+	var changes []string
+	for _, k := range sortedKeys(keys) {
+		oldField, inOld := oldFields[k]
+		newField, inNew := newFields[k]
+		switch {
+		case !inOld:
+			changes = append(changes, fmt.Sprintf("%s added with %s", k, newField))
+		case !inNew:
+			changes = append(changes, fmt.Sprintf("%s removed, was %s", k, oldField))
+		case oldField != newField:
+			changes = append(changes, fmt.Sprintf("%s changed from %s to %s", k, oldField, newField))
+		}
+	}
+	return changes
+}
+
+// parseConfigValue flattens a JSON, INI, PEM or YAML value into a map of field
+// paths to their JSON encoded values. It returns false when the value is not
+// in any of these formats.
+func parseConfigValue(value string) (map[string]string, bool) {
+	trimmed := strings.TrimSpace(value)
+	switch {
+	case trimmed == "":
+		return map[string]string{}, true
+	case json.Valid([]byte(trimmed)):
+		var v interface{}
+		if err := json.Unmarshal([]byte(trimmed), &v); err != nil {
+			return nil, false
+		}
+		fields := map[string]string{}
+		flattenConfigValue("", v, fields)
+		return fields, true
+	case strings.HasPrefix(trimmed, "["):
+		return parseINIConfigValue(trimmed)
+	case strings.HasPrefix(trimmed, "-----BEGIN"):
+		return parsePEMConfigValue(trimmed)
+	default:
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(trimmed), &v); err != nil {
+			return nil, false
+		}
+		if _, ok := v.(map[string]interface{}); !ok {
+			return nil, false
+		}
+		fields := map[string]string{}
+		flattenConfigValue("", v, fields)
+		return fields, true
+	}
+}
+
+func parseINIConfigValue(value string) (map[string]string, bool) {
+	// Keys can be repeated in the cloud provider configs, e.g., node-tags for GCP.
+	cfg, err := ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(value))
+	if err != nil {
+		return nil, false
+	}
+	fields := map[string]string{}
+	for _, section := range cfg.Sections() {
+		for _, key := range section.Keys() {
+			values := key.ValueWithShadows()
+			var v interface{} = values
+			if len(values) == 1 {
+				v = values[0]
+			}
+			fields[section.Name()+"."+key.Name()] = encodeConfigField(v)
+		}
+	}
+	return fields, true
+}
+
+func parsePEMConfigValue(value string) (map[string]string, bool) {
+	fields := map[string]string{}
+	rest := []byte(value)
+	for i := 0; ; i++ {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		fields[fmt.Sprintf("%s[%d]", block.Type, i)] = fmt.Sprintf("sha256:%x", sha256.Sum256(block.Bytes))
+	}
+	if len(fields) == 0 || len(bytes.TrimSpace(rest)) > 0 {
+		return nil, false
+	}
+	return fields, true
+}
+
+func flattenConfigValue(prefix string, v interface{}, fields map[string]string) {
+	m, ok := v.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		fields[prefix] = encodeConfigField(v)
+		return
+	}
+	for k, child := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		flattenConfigValue(path, child, fields)
+	}
+}
+
+func encodeConfigField(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package manifests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/mock"
+)
+
+func TestCloudProviderConfigDiff(t *testing.T) {
+	cases := []struct {
+		name         string
+		oldData      map[string]string
+		newData      map[string]string
+		expectedDiff string
+	}{{
+		name: "no configs",
+	}, {
+		name:         "config added",
+		newData:      map[string]string{ConfigDataKey: "[Global]\n"},
+		expectedDiff: "cloud provider config added",
+	}, {
+		name: "json whitespace and key order",
+		oldData: map[string]string{
+			ConfigDataKey: `{"cloud": "AzurePublicCloud", "location": "westeurope"}`,
+		},
+		newData: map[string]string{
+			ConfigDataKey: "{\n\t\"location\": \"westeurope\",\n\t\"cloud\": \"AzurePublicCloud\"\n}\n",
+		},
+	}, {
+		name: "json field changed",
+		oldData: map[string]string{
+			ConfigDataKey: `{"cloud": "AzurePublicCloud", "location": "westeurope"}`,
+		},
+		newData: map[string]string{
+			ConfigDataKey: `{"cloud": "AzurePublicCloud", "location": "eastus", "vmType": "standard"}`,
+		},
+		expectedDiff: `config: location changed from "westeurope" to "eastus"
+config: vmType added with "standard"`,
+	}, {
+		name: "ini key order and quoting",
+		oldData: map[string]string{
+			ConfigDataKey: "[Global]\nsecret-name = \"vsphere-creds\"\nsecret-namespace = kube-system\n",
+		},
+		newData: map[string]string{
+			ConfigDataKey: "[Global]\nsecret-namespace = kube-system\nsecret-name = vsphere-creds\n",
+		},
+	}, {
+		name: "ini field changed",
+		oldData: map[string]string{
+			ConfigDataKey: "[global]\nproject-id = a\nnode-tags = a-master\nnode-tags = a-worker\n",
+		},
+		newData: map[string]string{
+			ConfigDataKey: "[global]\nproject-id = b\nnode-tags = a-master\n",
+		},
+		expectedDiff: `config: global.node-tags changed from ["a-master","a-worker"] to "a-master"
+config: global.project-id changed from "a" to "b"`,
+	}, {
+		name: "yaml",
+		oldData: map[string]string{
+			ConfigDataKey: "global:\n  secretName: vsphere-creds\n  insecureFlag: true\n",
+		},
+		newData: map[string]string{
+			ConfigDataKey: "global:\n  insecureFlag: false\n  secretName: vsphere-creds\n",
+		},
+		expectedDiff: "config: global.insecureFlag changed from true to false",
+	}, {
+		name: "ca bundle unchanged",
+		oldData: map[string]string{
+			CABundleDataKey: testTrustBundle,
+		},
+		newData: map[string]string{
+			CABundleDataKey: "\n" + testTrustBundle + "\n",
+		},
+	}, {
+		name: "ca bundle removed",
+		oldData: map[string]string{
+			ConfigDataKey:   "[Global]\n",
+			CABundleDataKey: testTrustBundle,
+		},
+		newData: map[string]string{
+			ConfigDataKey: "[Global]\n",
+		},
+		expectedDiff: "ca-bundle.pem: removed",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldConfig, newConfig := &CloudProviderConfig{}, &CloudProviderConfig{}
+			if tc.oldData != nil {
+				oldConfig.ConfigMap = &corev1.ConfigMap{Data: tc.oldData}
+			}
+			if tc.newData != nil {
+				newConfig.ConfigMap = &corev1.ConfigMap{Data: tc.newData}
+			}
+			assert.Equal(t, tc.expectedDiff, oldConfig.Diff(newConfig))
+			assert.Equal(t, tc.expectedDiff == "", oldConfig.Equal(newConfig))
+		})
+	}
+}
+
+func TestCloudProviderConfigChangedFiles(t *testing.T) {
+	cpc := &CloudProviderConfig{
+		ConfigMap: &corev1.ConfigMap{Data: map[string]string{ConfigDataKey: "[Global]\nsecret-name = vsphere-creds\n"}},
+		File:      &asset.File{Filename: cloudProviderConfigFileName},
+	}
+
+	cases := []struct {
+		name     string
+		onDisk   string
+		err      error
+		expected []*asset.File
+	}{{
+		name:     "not on disk",
+		err:      os.ErrNotExist,
+		expected: []*asset.File{cpc.File},
+	}, {
+		name: "formatting only",
+		onDisk: `data:
+  config: |
+    [Global]
+    secret-name = "vsphere-creds"
+`,
+		expected: []*asset.File{},
+	}, {
+		name: "changed",
+		onDisk: `data:
+  config: |
+    [Global]
+    secret-name = other-creds
+`,
+		expected: []*asset.File{cpc.File},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+			var file *asset.File
+			if tc.err == nil {
+				file = &asset.File{Filename: cloudProviderConfigFileName, Data: []byte(tc.onDisk)}
+			}
+			fileFetcher.EXPECT().FetchByName(cloudProviderConfigFileName).Return(file, tc.err)
+
+			changed, err := cpc.ChangedFiles(fileFetcher)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tc.expected, changed)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package manifests
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnsupportedPlatform is matched by the error returned when the platform of
+// the install config is not supported by the cloud provider config.
+var ErrUnsupportedPlatform = errors.New("unsupported platform")
+
+// UnsupportedPlatformError is returned when the platform of the install config
+// is not supported by the cloud provider config.
+type UnsupportedPlatformError struct {
+	// Platform is the name of the platform of the install config.
+	Platform string
+	// Supported are the names of the supported platforms.
+	Supported []string
+}
+
+func (e *UnsupportedPlatformError) Error() string {
+	return fmt.Sprintf("cloud provider config: unsupported platform %q (supported: %s)", e.Platform, strings.Join(e.Supported, ", "))
+}
+
+// Is makes the error match ErrUnsupportedPlatform.
+func (e *UnsupportedPlatformError) Is(target error) bool {
+	return target == ErrUnsupportedPlatform
+}
+
+// CredentialsError is returned when the credentials for the cloud cannot be
+// loaded or are rejected by the cloud.
+type CredentialsError struct {
+	// Platform is the name of the platform the credentials are for.
+	Platform string
+	// Err is the underlying error, whose message is the one of the error.
+	Err error
+}
+
+func (e *CredentialsError) Error() string { return e.Err.Error() }
+
+func (e *CredentialsError) Unwrap() error { return e.Err }
+
+// RemoteLookupError is returned when looking up the resources of the cluster
+// from the cloud fails, e.g. its networks or zones.
+type RemoteLookupError struct {
+	// Platform is the name of the platform of the cloud.
+	Platform string
+	// Err is the underlying error, whose message is the one of the error.
+	Err error
+}
+
+func (e *RemoteLookupError) Error() string { return e.Err.Error() }
+
+func (e *RemoteLookupError) Unwrap() error { return e.Err }
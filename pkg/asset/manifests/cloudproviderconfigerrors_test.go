@@ -0,0 +1,60 @@
+package manifests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	icibmcloud "github.com/openshift/installer/pkg/asset/installconfig/ibmcloud"
+	"github.com/openshift/installer/pkg/types"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+)
+
+func TestCloudProviderConfigUnsupportedPlatformError(t *testing.T) {
+	clusterID := &installconfig.ClusterID{InfraID: "test-infra-id"}
+	_, err := BuildCloudProviderConfigMap(context.Background(), installconfig.MakeAsset(icBuild.build()), clusterID)
+
+	assert.True(t, errors.Is(err, ErrUnsupportedPlatform))
+	var unsupported *UnsupportedPlatformError
+	if assert.True(t, errors.As(err, &unsupported)) {
+		assert.Equal(t, "", unsupported.Platform)
+		assert.Contains(t, unsupported.Supported, "aws")
+	}
+	assert.False(t, errors.As(err, new(*CredentialsError)))
+	assert.False(t, errors.As(err, new(*RemoteLookupError)))
+}
+
+func TestCloudProviderConfigIBMCloudErrors(t *testing.T) {
+	t.Setenv("OPENSHIFT_INSTALL_IBMCLOUD_ACCOUNT_ID_TIMEOUT", "10ms")
+	installConfig := icBuild.build(func(ic *types.InstallConfig) {
+		ic.Platform.IBMCloud = &ibmcloudtypes.Platform{
+			Region: "us-south",
+			DefaultMachinePlatform: &ibmcloudtypes.MachinePool{
+				Zones: []string{"us-south-1"},
+			},
+		}
+		ic.ControlPlane = &types.MachinePool{Name: types.MachinePoolControlPlaneRoleName}
+		ic.Compute = []types.MachinePool{{Name: types.MachinePoolComputeRoleName}}
+	})
+	installConfigAsset := installconfig.MakeAsset(installConfig)
+	installConfigAsset.IBMCloud = icibmcloud.NewMetadata(installConfig)
+	clusterID := &installconfig.ClusterID{InfraID: "test-infra-id"}
+
+	_, err := BuildCloudProviderConfigMap(context.Background(), installConfigAsset, clusterID, withIBMCloudAccountIDResolver(&fakeAccountIDResolver{err: errors.New("invalid API key")}))
+	assert.EqualError(t, err, "invalid API key")
+	var credentialsErr *CredentialsError
+	if assert.True(t, errors.As(err, &credentialsErr)) {
+		assert.Equal(t, ibmcloudtypes.Name, credentialsErr.Platform)
+	}
+
+	_, err = BuildCloudProviderConfigMap(context.Background(), installConfigAsset, clusterID, withIBMCloudAccountIDResolver(&fakeAccountIDResolver{block: true}))
+	var lookupErr *RemoteLookupError
+	if assert.True(t, errors.As(err, &lookupErr)) {
+		assert.Equal(t, ibmcloudtypes.Name, lookupErr.Platform)
+	}
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.False(t, errors.As(err, new(*CredentialsError)))
+}
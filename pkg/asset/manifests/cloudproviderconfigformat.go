@@ -0,0 +1,93 @@
+package manifests
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	ini "gopkg.in/ini.v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/api/features"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+	nutanixtypes "github.com/openshift/installer/pkg/types/nutanix"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+	powervstypes "github.com/openshift/installer/pkg/types/powervs"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// cloudProviderConfigFormat is the format the cloud provider of a platform
+// reads its config in.
+type cloudProviderConfigFormat string
+
+const (
+	iniCloudProviderConfigFormat  cloudProviderConfigFormat = "INI"
+	jsonCloudProviderConfigFormat cloudProviderConfigFormat = "JSON"
+	yamlCloudProviderConfigFormat cloudProviderConfigFormat = "YAML"
+)
+
+// cloudProviderConfigFormats are the formats of the config of the platforms
+// with a cloud provider config. The config of the external platforms is given
+// by the user as is, so it is not checked.
+var cloudProviderConfigFormats = map[string]func(*types.InstallConfig) cloudProviderConfigFormat{
+	awstypes.Name:       staticCloudProviderConfigFormat(iniCloudProviderConfigFormat),
+	azuretypes.Name:     staticCloudProviderConfigFormat(jsonCloudProviderConfigFormat),
+	gcptypes.Name:       staticCloudProviderConfigFormat(iniCloudProviderConfigFormat),
+	ibmcloudtypes.Name:  staticCloudProviderConfigFormat(iniCloudProviderConfigFormat),
+	nutanixtypes.Name:   staticCloudProviderConfigFormat(jsonCloudProviderConfigFormat),
+	openstacktypes.Name: staticCloudProviderConfigFormat(iniCloudProviderConfigFormat),
+	powervstypes.Name:   staticCloudProviderConfigFormat(iniCloudProviderConfigFormat),
+	vspheretypes.Name: func(ic *types.InstallConfig) cloudProviderConfigFormat {
+		if ic.EnabledFeatureGates().Enabled(features.FeatureGateVSphereMultiVCenters) {
+			return yamlCloudProviderConfigFormat
+		}
+		return iniCloudProviderConfigFormat
+	},
+}
+
+func staticCloudProviderConfigFormat(format cloudProviderConfigFormat) func(*types.InstallConfig) cloudProviderConfigFormat {
+	return func(*types.InstallConfig) cloudProviderConfigFormat {
+		return format
+	}
+}
+
+// validateCloudProviderConfigFormat checks that the config of the platform
+// parses in the format its cloud provider reads, to catch a generator, an
+// addition or an override producing malformed output before it is written.
+func validateCloudProviderConfigFormat(ic *types.InstallConfig, data map[string]string) error {
+	platformName := ic.Platform.Name()
+	formatOf, ok := cloudProviderConfigFormats[platformName]
+	if !ok {
+		return nil
+	}
+	config, ok := data[ConfigDataKey]
+	if !ok {
+		return nil
+	}
+
+	format := formatOf(ic)
+	if err := parseCloudProviderConfigFormat(format, config); err != nil {
+		return errors.Wrapf(err, "the %s cloud provider config is not valid %s", platformName, format)
+	}
+	return nil
+}
+
+// parseCloudProviderConfigFormat checks that the config parses in the format.
+func parseCloudProviderConfigFormat(format cloudProviderConfigFormat, config string) error {
+	var err error
+	switch format {
+	case iniCloudProviderConfigFormat:
+		// Keys can be repeated in the cloud provider configs, e.g., node-tags for GCP.
+		_, err = ini.LoadSources(ini.LoadOptions{AllowShadows: true}, []byte(config))
+	case jsonCloudProviderConfigFormat:
+		var v map[string]interface{}
+		err = json.Unmarshal([]byte(config), &v)
+	case yamlCloudProviderConfigFormat:
+		var v map[string]interface{}
+		err = yaml.Unmarshal([]byte(config), &v)
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/types"
+)
+
+func TestValidateCloudProviderConfigFormat(t *testing.T) {
+	cases := []struct {
+		name          string
+		installConfig *types.InstallConfig
+		config        string
+		expectedError string
+	}{{
+		name:          "valid INI",
+		installConfig: icBuild.build(icBuild.forAWS()),
+		config:        "[Global]\nRoleARN = arn:aws:iam::123456789012:role/test\n",
+	}, {
+		name:          "malformed INI",
+		installConfig: icBuild.build(icBuild.forAWS()),
+		config:        "[Global]\nRoleARN\n",
+		expectedError: `^the aws cloud provider config is not valid INI: `,
+	}, {
+		name:          "valid JSON",
+		installConfig: icBuild.build(icBuild.forAzure()),
+		config:        `{"cloud": "AzurePublicCloud"}`,
+	}, {
+		name:          "malformed JSON",
+		installConfig: icBuild.build(icBuild.forAzure()),
+		config:        `{"cloud": "AzurePublicCloud",}`,
+		expectedError: `^the azure cloud provider config is not valid JSON: `,
+	}, {
+		name:          "JSON not an object",
+		installConfig: icBuild.build(icBuild.forAzure()),
+		config:        `["AzurePublicCloud"]`,
+		expectedError: `^the azure cloud provider config is not valid JSON: `,
+	}, {
+		name:          "platform without cloud provider config",
+		installConfig: icBuild.build(icBuild.forNone()),
+		config:        "not a config",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateCloudProviderConfigFormat(tc.installConfig, map[string]string{ConfigDataKey: tc.config})
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Regexp(t, tc.expectedError, err)
+		})
+	}
+}
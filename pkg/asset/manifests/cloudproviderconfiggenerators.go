@@ -0,0 +1,676 @@
+package manifests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	azureenv "github.com/Azure/go-autorest/autorest/azure"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/api/features"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	icazure "github.com/openshift/installer/pkg/asset/installconfig/azure"
+	ibmcloudmachines "github.com/openshift/installer/pkg/asset/machines/ibmcloud"
+	"github.com/openshift/installer/pkg/asset/manifests/azure"
+	"github.com/openshift/installer/pkg/asset/manifests/capiutils"
+	gcpmanifests "github.com/openshift/installer/pkg/asset/manifests/gcp"
+	ibmcloudmanifests "github.com/openshift/installer/pkg/asset/manifests/ibmcloud"
+	nutanixmanifests "github.com/openshift/installer/pkg/asset/manifests/nutanix"
+	openstackmanifests "github.com/openshift/installer/pkg/asset/manifests/openstack"
+	powervsmanifests "github.com/openshift/installer/pkg/asset/manifests/powervs"
+	vspheremanifests "github.com/openshift/installer/pkg/asset/manifests/vsphere"
+	"github.com/openshift/installer/pkg/types"
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	externaltypes "github.com/openshift/installer/pkg/types/external"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+	nutanixtypes "github.com/openshift/installer/pkg/types/nutanix"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+	powervstypes "github.com/openshift/installer/pkg/types/powervs"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// cloudProviderConfigRequest holds what the generators need to fill the data
+// of the cloud provider config ConfigMap.
+type cloudProviderConfigRequest struct {
+	installConfig *installconfig.InstallConfig
+	clusterID     *installconfig.ClusterID
+	options       *cloudProviderConfigOptions
+
+	// offline is set when the lookups of the generators must be replaced
+	// with placeholders.
+	offline bool
+	// usedPlaceholders is set by the generators which replaced lookups with
+	// placeholders.
+	usedPlaceholders bool
+}
+
+// cloudProviderConfigGenerator fills the data of the cloud provider config
+// ConfigMap for a platform.
+type cloudProviderConfigGenerator func(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error
+
+// cloudProviderConfigGenerators are the generators of the platforms with a
+// cloud provider config, by platform name.
+var cloudProviderConfigGenerators = map[string]cloudProviderConfigGenerator{}
+
+// cloudProviderConfigDataKeys are the data keys the generators can set, by
+// platform name. The platforms without a generator only get the keys of an
+// external cloud provider.
+var cloudProviderConfigDataKeys = map[string][]string{
+	externaltypes.Name: {ConfigDataKey, ProviderDataKey},
+}
+
+// registerCloudProviderConfigGenerator registers the generator of the cloud
+// provider config of the platform, along with the data keys it can set. It is
+// meant to be called from init, and panics when the platform already has a
+// generator.
+func registerCloudProviderConfigGenerator(platformName string, generator cloudProviderConfigGenerator, dataKeys ...string) {
+	if _, ok := cloudProviderConfigGenerators[platformName]; ok {
+		panic(fmt.Sprintf("cloud provider config generator already registered for platform %s", platformName))
+	}
+	cloudProviderConfigGenerators[platformName] = generator
+	cloudProviderConfigDataKeys[platformName] = dataKeys
+}
+
+func init() {
+	registerCloudProviderConfigGenerator(awstypes.Name, generateAWSCloudProviderConfig, ConfigDataKey, CABundleDataKey)
+	registerCloudProviderConfigGenerator(openstacktypes.Name, generateOpenStackCloudProviderConfig, ConfigDataKey, CABundleDataKey)
+	registerCloudProviderConfigGenerator(azuretypes.Name, generateAzureCloudProviderConfig, ConfigDataKey, CABundleDataKey, EndpointsKey)
+	registerCloudProviderConfigGenerator(gcptypes.Name, generateGCPCloudProviderConfig, ConfigDataKey, CABundleDataKey)
+	registerCloudProviderConfigGenerator(ibmcloudtypes.Name, generateIBMCloudCloudProviderConfig, ConfigDataKey)
+	registerCloudProviderConfigGenerator(powervstypes.Name, generatePowerVSCloudProviderConfig, ConfigDataKey)
+	registerCloudProviderConfigGenerator(vspheretypes.Name, generateVSphereCloudProviderConfig, ConfigDataKey, ExternalConfigDataKey)
+	registerCloudProviderConfigGenerator(nutanixtypes.Name, generateNutanixCloudProviderConfig, ConfigDataKey)
+}
+
+// cloudProviderConfigInfraIDPlatforms are the platforms whose generators name
+// the cluster resources in the cloud provider config after the infrastructure
+// ID, so an empty ID produces names such as "-vnet".
+var cloudProviderConfigInfraIDPlatforms = sets.New(azuretypes.Name, gcptypes.Name, ibmcloudtypes.Name, powervstypes.Name, vspheretypes.Name)
+
+// ExpectedDataKeys returns the sorted data keys the cloud provider config of
+// the platform can hold. Only some of them are set for a given install
+// config, e.g. the CA bundle is only set on the AWS isolated regions. No keys
+// are returned for the platforms without a cloud provider config, besides the
+// external platform, and for unknown platforms.
+func ExpectedDataKeys(platformName string) []string {
+	return sets.List(sets.New(cloudProviderConfigDataKeys[platformName]...))
+}
+
+// generateAWSCloudProviderConfig fills the cloud provider config for AWS.
+func generateAWSCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig := req.installConfig
+
+	// Store the additional trust bundle in the ca-bundle.pem key if the cluster is being installed on an isolated region.
+	trustBundle := installConfig.Config.AdditionalTrustBundle
+	if trustBundle != "" && awstypes.IsIsolatedRegion(installConfig.Config.AWS.Region) {
+		cm.Data[CABundleDataKey] = trustBundle
+	}
+
+	// Include a non-empty kube config to appease components--such as the kube-apiserver--that
+	// expect there to be a kube config if the cloud-provider-config ConfigMap exists. See
+	// https://bugzilla.redhat.com/show_bug.cgi?id=1926975.
+	// Note that the newline is required in order to be valid yaml.
+	awsConfig := `[Global]
+`
+	if roleARN := installConfig.Config.AWS.CloudProviderRoleARN; roleARN != "" {
+		awsConfig += "RoleARN = " + roleARN + "\n"
+	}
+	// The cloud provider resolves the endpoints in the global partition, so the
+	// endpoints of the China partition are set explicitly.
+	if region := installConfig.Config.AWS.Region; awstypes.IsChinaRegion(region) {
+		overrides, err := awsServiceOverrides(region)
+		if err != nil {
+			return err
+		}
+		awsConfig += overrides
+	}
+	cm.Data[ConfigDataKey] = awsConfig
+	return nil
+}
+
+// awsCloudProviderServices are the services called by the AWS cloud provider.
+var awsCloudProviderServices = []string{"ec2", "elasticloadbalancing"}
+
+// validateAzureCredentials checks that the credentials of the session identify
+// the subscription and tenant, since the cloud provider config is valid without
+// them but the cloud provider fails once the cluster is up. The client ID is
+// not checked, it is empty when a system-assigned managed identity is used.
+func validateAzureCredentials(credentials *icazure.Credentials) error {
+	var missing []string
+	if credentials.SubscriptionID == "" {
+		missing = append(missing, "subscription ID")
+	}
+	if credentials.TenantID == "" {
+		missing = append(missing, "tenant ID")
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("the Azure credentials have no %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// validateAzureResourceGroups checks that the Azure cloud provider config uses
+// the existing resource group of the cluster, when one is set, after the
+// additions and the overrides are applied, since the cloud provider manages the
+// resources of another group otherwise. The virtual network is in that group
+// too unless it is in an existing network resource group.
+func validateAzureResourceGroups(ic *types.InstallConfig, data map[string]string) error {
+	if ic.Platform.Name() != azuretypes.Name || ic.Azure.ResourceGroupName == "" {
+		return nil
+	}
+	config, ok := data[ConfigDataKey]
+	if !ok {
+		return nil
+	}
+	var fields struct {
+		ResourceGroup     string `json:"resourceGroup"`
+		VnetResourceGroup string `json:"vnetResourceGroup"`
+	}
+	if err := json.Unmarshal([]byte(config), &fields); err != nil {
+		return errors.Wrap(err, "failed to parse the Azure cloud provider config")
+	}
+	if fields.ResourceGroup != ic.Azure.ResourceGroupName {
+		return errors.Errorf("the resourceGroup %q of the Azure cloud provider config does not match the existing resource group %q of platform.azure.resourceGroupName", fields.ResourceGroup, ic.Azure.ResourceGroupName)
+	}
+	if ic.Azure.NetworkResourceGroupName == "" && fields.VnetResourceGroup != ic.Azure.ResourceGroupName {
+		return errors.Errorf("the vnetResourceGroup %q of the Azure cloud provider config does not match the existing resource group %q of platform.azure.resourceGroupName, set platform.azure.networkResourceGroupName for a virtual network in another resource group", fields.VnetResourceGroup, ic.Azure.ResourceGroupName)
+	}
+	return nil
+}
+
+// awsServiceOverrides returns the ServiceOverride sections pointing the AWS
+// cloud provider to the endpoints of its services in the partition of the
+// region.
+func awsServiceOverrides(region string) (string, error) {
+	buf := &bytes.Buffer{}
+	for i, service := range awsCloudProviderServices {
+		endpoint, err := endpoints.DefaultResolver().EndpointFor(service, region, endpoints.StrictMatchingOption)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to resolve the %s endpoint of region %s", service, region)
+		}
+		signingRegion := endpoint.SigningRegion
+		if signingRegion == "" {
+			signingRegion = region
+		}
+		fmt.Fprintf(buf, "\n[ServiceOverride %q]\n", strconv.Itoa(i))
+		fmt.Fprintf(buf, "Service = %s\n", service)
+		fmt.Fprintf(buf, "Region = %s\n", region)
+		fmt.Fprintf(buf, "URL = %s\n", endpoint.URL)
+		fmt.Fprintf(buf, "SigningRegion = %s\n", signingRegion)
+	}
+	return buf.String(), nil
+}
+
+// generateOpenStackCloudProviderConfig fills the cloud provider config for OpenStack.
+func generateOpenStackCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig := req.installConfig
+
+	var cloudProviderConfigData, cloudProviderConfigCABundleData string
+	var err error
+	if ref := req.options.openstackCloudsSecret; ref != nil {
+		var cloudsYAML []byte
+		cloudsYAML, err = openstackCloudsYAML(ctx, ref)
+		if err != nil {
+			return &CredentialsError{Platform: openstacktypes.Name, Err: err}
+		}
+		cloudProviderConfigData, cloudProviderConfigCABundleData, err = openstackmanifests.GenerateCloudProviderConfigFromCloudsYAML(ctx, *installConfig.Config, cloudsYAML)
+	} else {
+		cloudProviderConfigData, cloudProviderConfigCABundleData, err = openstackmanifests.GenerateCloudProviderConfig(ctx, *installConfig.Config)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to generate OpenStack provider config")
+	}
+	if err := validateOpenStackCloudProviderConfig(cloudProviderConfigData); err != nil {
+		return errors.Wrap(err, "failed to generate OpenStack provider config")
+	}
+	cm.Data[ConfigDataKey] = cloudProviderConfigData
+	if cloudProviderConfigCABundleData != "" {
+		cm.Data[CABundleDataKey] = cloudProviderConfigCABundleData
+	}
+	return nil
+}
+
+// validateOpenStackCloudProviderConfig checks that the OpenStack cloud provider
+// config has a [Global] section, since the cloud provider does not find the
+// credentials of the cloud without it.
+func validateOpenStackCloudProviderConfig(config string) error {
+	if strings.TrimSpace(config) == "" {
+		return errors.New("the cloud provider config is empty")
+	}
+	for _, line := range strings.Split(config, "\n") {
+		if strings.TrimSpace(line) == "[Global]" {
+			return nil
+		}
+	}
+	return errors.New("the cloud provider config has no [Global] section")
+}
+
+// openstackCloudsYAMLKey is the key of clouds.yaml in the data of the Secret
+// holding the OpenStack credentials.
+const openstackCloudsYAMLKey = "clouds.yaml"
+
+// openstackCloudsYAML returns the clouds.yaml of the referenced Secret.
+func openstackCloudsYAML(ctx context.Context, ref *secretReference) ([]byte, error) {
+	secret, err := ref.fetcher.GetSecret(ctx, ref.namespace, ref.name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the clouds.yaml Secret %s/%s", ref.namespace, ref.name)
+	}
+	if secret == nil {
+		return nil, errors.Errorf("the clouds.yaml Secret %s/%s was not found", ref.namespace, ref.name)
+	}
+	if cloudsYAML, ok := secret.Data[openstackCloudsYAMLKey]; ok && len(cloudsYAML) > 0 {
+		return cloudsYAML, nil
+	}
+	if cloudsYAML, ok := secret.StringData[openstackCloudsYAMLKey]; ok && cloudsYAML != "" {
+		return []byte(cloudsYAML), nil
+	}
+	return nil, errors.Errorf("the Secret %s/%s has no %s key", ref.namespace, ref.name, openstackCloudsYAMLKey)
+}
+
+// generateAzureCloudProviderConfig fills the cloud provider config for Azure.
+func generateAzureCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig, clusterID := req.installConfig, req.clusterID
+
+	if err := azure.ValidateNetworking(installConfig.Config); err != nil {
+		return errors.Wrap(err, "could not create cloud provider config")
+	}
+
+	session := req.options.azureSession
+	if session == nil && req.offline {
+		session = &icazure.Session{
+			Credentials: icazure.Credentials{
+				SubscriptionID: offlinePlaceholder,
+				TenantID:       offlinePlaceholder,
+			},
+		}
+		if installConfig.Config.Azure.CloudName == azuretypes.StackCloud {
+			session.Environment.ResourceManagerEndpoint = installConfig.Config.Azure.ARMEndpoint
+			session.Environment.ActiveDirectoryEndpoint = offlinePlaceholder
+			session.Environment.GraphEndpoint = offlinePlaceholder
+			session.Environment.GalleryEndpoint = offlinePlaceholder
+		}
+		req.usedPlaceholders = true
+	}
+	if session == nil {
+		if installConfig.Azure == nil {
+			return errors.New("azure platform selected but the Azure metadata of the install config is missing")
+		}
+		var err error
+		session, err = getAzureSession(ctx, installConfig.Azure.Session)
+		if err != nil {
+			return &CredentialsError{Platform: azuretypes.Name, Err: errors.Wrap(err, "could not get azure session")}
+		}
+	}
+	if !req.usedPlaceholders {
+		if err := validateAzureCredentials(&session.Credentials); err != nil {
+			return &CredentialsError{Platform: azuretypes.Name, Err: err}
+		}
+	}
+
+	resourcePrefix := installConfig.Config.Azure.CloudProviderResourcePrefix(clusterID.InfraID)
+	nsg := installConfig.Config.Azure.NetworkSecurityGroupName(clusterID.InfraID)
+	nrg := installConfig.Config.Azure.ClusterResourceGroupName(clusterID.InfraID)
+	if installConfig.Config.Azure.NetworkResourceGroupName != "" {
+		nrg = installConfig.Config.Azure.NetworkResourceGroupName
+	}
+	vnet, subnet, err := azure.VirtualNetworkAndComputeSubnet(installConfig.Config.Azure, clusterID.InfraID)
+	if err != nil {
+		return errors.Wrap(err, "could not create cloud provider config")
+	}
+	azureParams := azure.CloudProviderConfig{
+		CloudName:                 installConfig.Config.Azure.CloudName,
+		ResourceGroupName:         installConfig.Config.Azure.ClusterResourceGroupName(clusterID.InfraID),
+		GroupLocation:             installConfig.Config.Azure.Region,
+		ResourcePrefix:            resourcePrefix,
+		SubscriptionID:            session.Credentials.SubscriptionID,
+		TenantID:                  session.Credentials.TenantID,
+		NetworkResourceGroupName:  nrg,
+		NetworkSecurityGroupName:  nsg,
+		VirtualNetworkName:        vnet,
+		SubnetName:                subnet,
+		ResourceManagerEndpoint:   installConfig.Config.Azure.ARMEndpoint,
+		ARO:                       installConfig.Config.Azure.IsARO(),
+		PutVMSSVMBatchSize:        installConfig.Config.Azure.PutVMSSVMBatchSize,
+		LoadBalancerResourceGroup: installConfig.Config.Azure.LoadBalancerResourceGroupName,
+		LoadBalancerSku:           string(installConfig.Config.Azure.LoadBalancerSKU),
+		OutboundType:              installConfig.Config.Azure.OutboundType,
+		Tags:                      installConfig.Config.Azure.UserTags,
+		ServiceEndpoints:          installConfig.Config.Azure.ServiceEndpoints,
+		ClusterName:               clusterID.InfraID,
+		LoadBalancers:             installConfig.Config.Azure.LoadBalancers,
+		RouteTableName:            installConfig.Config.Azure.RouteTableName,
+		Minimal:                   req.options.minimalAzureConfig,
+	}
+	// Azure Stack has no pre-defined environment, so the endpoints of the
+	// registered environment, or else the ones discovered from the ARM
+	// endpoint, are passed to the cloud provider.
+	var stackEnv azureenv.Environment
+	if installConfig.Config.Azure.CloudName == azuretypes.StackCloud {
+		stackEnv, err = azure.ResolveEnvironment(installConfig.Config.Azure.CloudName, session.Environment)
+		if err != nil {
+			return errors.Wrap(err, "could not resolve the Azure Stack environment")
+		}
+		azureParams.ActiveDirectoryEndpoint = stackEnv.ActiveDirectoryEndpoint
+		azureParams.GraphEndpoint = stackEnv.GraphEndpoint
+		azureParams.GalleryEndpoint = stackEnv.GalleryEndpoint
+		// Azure Stack has no availability zones, so the machines are placed in
+		// the availability set of the cluster, which the cloud provider needs
+		// to configure the backends of the load balancers.
+		azureParams.VMType = "standard"
+		azureParams.PrimaryAvailabilitySetName = fmt.Sprintf("%s-cluster", clusterID.InfraID)
+	}
+	azureConfig, err := azureParams.JSON()
+	if err != nil {
+		return errors.Wrap(err, "could not create cloud provider config")
+	}
+	cm.Data[ConfigDataKey] = azureConfig
+
+	if installConfig.Config.Azure.CloudName == azuretypes.StackCloud {
+		endpoints, err := azure.EndpointsJSON(stackEnv)
+		if err != nil {
+			return errors.Wrap(err, "could not serialize Azure Stack endpoints")
+		}
+		cm.Data[EndpointsKey] = endpoints
+
+		// The ARM endpoint of Azure Stack Hub is usually served with a
+		// certificate signed by a private CA, which the cloud provider needs
+		// to reach it. The bundle is validated with the rest of the data.
+		if trustBundle := installConfig.Config.AdditionalTrustBundle; trustBundle != "" {
+			cm.Data[CABundleDataKey] = trustBundle
+		}
+	}
+	return nil
+}
+
+// generateGCPCloudProviderConfig fills the cloud provider config for GCP.
+func generateGCPCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig, clusterID := req.installConfig, req.clusterID
+
+	subnet := fmt.Sprintf("%s-worker-subnet", clusterID.InfraID)
+	if installConfig.Config.GCP.ComputeSubnet != "" {
+		subnet = installConfig.Config.GCP.ComputeSubnet
+	}
+	if installConfig.Config.GCP.ProjectID == "" {
+		return errors.New("GCP project ID is required for cloud provider config")
+	}
+	gcpParams := gcpmanifests.CloudProviderConfig{
+		InfraID:            clusterID.InfraID,
+		ProjectID:          installConfig.Config.GCP.ProjectID,
+		SubnetName:         subnet,
+		NetworkProjectID:   installConfig.Config.GCP.NetworkProjectID,
+		ServiceEndpoints:   installConfig.Config.GCP.ServiceEndpoints,
+		CredentialsMode:    installConfig.Config.CredentialsMode,
+		Zone:               gcpmanifests.SingleZone(installConfig.Config),
+		DualStack:          gcpmanifests.IsDualStack(installConfig.Config.Networking),
+		NodeInstancePrefix: installConfig.Config.GCP.NodeInstancePrefix,
+		NetworkName:        installConfig.Config.GCP.Network,
+	}
+	gcpConfig, err := gcpParams.INI()
+	if err != nil {
+		return errors.Wrap(err, "could not create cloud provider config")
+	}
+	cm.Data[ConfigDataKey] = gcpConfig
+
+	// Custom endpoints can be private ones served with a certificate signed
+	// by the additional trust bundle, which the cloud provider then needs.
+	trustBundle := installConfig.Config.AdditionalTrustBundle
+	if trustBundle != "" && gcpmanifests.HasCloudProviderServiceEndpoints(installConfig.Config.GCP.ServiceEndpoints) {
+		cm.Data[CABundleDataKey] = trustBundle
+	}
+	return nil
+}
+
+// generateIBMCloudCloudProviderConfig fills the cloud provider config for IBM Cloud.
+func generateIBMCloudCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig, clusterID := req.installConfig, req.clusterID
+
+	if installConfig.IBMCloud == nil && !req.offline {
+		return errors.New("ibmcloud platform selected but the IBM Cloud metadata of the install config is missing")
+	}
+	var resolver accountIDResolver = installConfig.IBMCloud
+	if req.options.ibmcloudAccountIDResolver != nil {
+		resolver = req.options.ibmcloudAccountIDResolver
+	}
+	accountID := offlinePlaceholder
+	if req.offline {
+		req.usedPlaceholders = true
+	} else {
+		timeout := ibmcloudAccountIDTimeout()
+		accountIDCtx, cancel := context.WithTimeout(ctx, timeout)
+		var err error
+		accountID, err = resolver.AccountID(accountIDCtx)
+		timedOut := errors.Is(accountIDCtx.Err(), context.DeadlineExceeded)
+		cancel()
+		if err != nil {
+			if timedOut {
+				return &RemoteLookupError{Platform: ibmcloudtypes.Name, Err: errors.Wrapf(err, "timed out after %s retrieving the IBM Cloud account ID, check connectivity to the IAM endpoint", timeout)}
+			}
+			return &CredentialsError{Platform: ibmcloudtypes.Name, Err: err}
+		}
+	}
+
+	subnetNames := []string{}
+	if req.offline {
+		// Only the names of the subnets are used, which are already known.
+		subnetNames = append(subnetNames, installConfig.Config.IBMCloud.ControlPlaneSubnets...)
+		subnetNames = append(subnetNames, installConfig.Config.IBMCloud.ComputeSubnets...)
+	} else {
+		cpSubnets, err := installConfig.IBMCloud.ControlPlaneSubnets(ctx)
+		if err != nil {
+			return &RemoteLookupError{Platform: ibmcloudtypes.Name, Err: errors.Wrap(err, "could not retrieve IBM Cloud control plane subnets")}
+		}
+		for _, cpSubnet := range cpSubnets {
+			subnetNames = append(subnetNames, cpSubnet.Name)
+		}
+
+		computeSubnets, err := installConfig.IBMCloud.ComputeSubnets(ctx)
+		if err != nil {
+			return &RemoteLookupError{Platform: ibmcloudtypes.Name, Err: errors.Wrap(err, "could not retrieve IBM Cloud compute subnets")}
+		}
+		for _, computeSubnet := range computeSubnets {
+			subnetNames = append(subnetNames, computeSubnet.Name)
+		}
+	}
+
+	controlPlane := &ibmcloudtypes.MachinePool{}
+	controlPlane.Set(installConfig.Config.Platform.IBMCloud.DefaultMachinePlatform)
+	controlPlane.Set(installConfig.Config.ControlPlane.Platform.IBMCloud)
+	compute := &ibmcloudtypes.MachinePool{}
+	compute.Set(installConfig.Config.Platform.IBMCloud.DefaultMachinePlatform)
+	compute.Set(installConfig.Config.WorkerMachinePool().Platform.IBMCloud)
+
+	if len(controlPlane.Zones) == 0 || len(compute.Zones) == 0 {
+		zones := []string{offlinePlaceholder}
+		if req.offline {
+			req.usedPlaceholders = true
+		} else {
+			var err error
+			zones, err = ibmcloudmachines.AvailabilityZones(installConfig.Config.IBMCloud.Region, installConfig.Config.Platform.IBMCloud.ServiceEndpoints)
+			if err != nil {
+				return &RemoteLookupError{Platform: ibmcloudtypes.Name, Err: errors.Wrapf(err, "could not get availability zones for %s", installConfig.Config.IBMCloud.Region)}
+			}
+		}
+		if len(controlPlane.Zones) == 0 {
+			controlPlane.Zones = zones
+		}
+		if len(compute.Zones) == 0 {
+			compute.Zones = zones
+		}
+	}
+
+	ibmcloudConfig, err := ibmcloudmanifests.CloudProviderConfig(
+		clusterID.InfraID,
+		accountID,
+		installConfig.Config.IBMCloud.Region,
+		installConfig.Config.Platform.IBMCloud.ClusterResourceGroupName(clusterID.InfraID),
+		installConfig.Config.Platform.IBMCloud.GetVPCName(),
+		subnetNames,
+		controlPlane.Zones,
+		compute.Zones,
+		installConfig.Config.Platform.IBMCloud.ServiceEndpoints,
+		installConfig.Config.Platform.IBMCloud.LoadBalancerProfile,
+		installConfig.Config.Platform.IBMCloud.Satellite,
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not create cloud provider config")
+	}
+	cm.Data[ConfigDataKey] = ibmcloudConfig
+	return nil
+}
+
+// generatePowerVSCloudProviderConfig fills the cloud provider config for PowerVS.
+func generatePowerVSCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig, clusterID := req.installConfig, req.clusterID
+
+	if installConfig.PowerVS == nil {
+		return errors.New("powervs platform selected but the PowerVS metadata of the install config is missing")
+	}
+	var (
+		accountID, vpcRegion string
+		err                  error
+	)
+
+	if accountID, err = installConfig.PowerVS.AccountID(ctx); err != nil {
+		return &CredentialsError{Platform: powervstypes.Name, Err: err}
+	}
+
+	vpcRegion = installConfig.Config.PowerVS.VPCRegion
+	if vpcRegion == "" {
+		vpcRegion, err = powervstypes.VPCRegionForPowerVSRegion(installConfig.Config.PowerVS.Region)
+	}
+	if err != nil {
+		return err
+	}
+
+	vpc := installConfig.Config.PowerVS.VPCName
+	vpcSubnets := installConfig.Config.PowerVS.VPCSubnets
+	if vpc == "" {
+		vpc = fmt.Sprintf("vpc-%s", clusterID.InfraID)
+	} else {
+		existingSubnets, err := installConfig.PowerVS.GetVPCSubnets(ctx, vpc)
+		if err != nil {
+			return &RemoteLookupError{Platform: powervstypes.Name, Err: err}
+		}
+
+		// cluster-api-provider-ibm requires any existing VPC subnet to be specified in the cluster
+		// manifest and as such we need to also specify these in the cloudproviderconfig.
+		// @TODO: Deprecate platform.powervs.vpcSubnets?
+		for _, subnet := range existingSubnets {
+			vpcSubnets = append(vpcSubnets, *subnet.Name)
+		}
+	}
+
+	if len(vpcSubnets) == 0 {
+		if capiutils.IsEnabled(installConfig) {
+			vpcZones, err := powervstypes.AvailableVPCZones(installConfig.Config.PowerVS.Region)
+			if err != nil {
+				return err
+			}
+
+			// The PowerVS CAPI provider generates three subnets.  One for
+			// each of the endpoint.
+			// @TODO the provider should export a function which gives us
+			// an array
+			for _, zone := range vpcZones {
+				vpcSubnets = append(vpcSubnets,
+					fmt.Sprintf("%s-vpcsubnet-%s", clusterID.InfraID, zone))
+			}
+		} else {
+			vpcSubnets = append(vpcSubnets, fmt.Sprintf("vpc-subnet-%s", clusterID.InfraID))
+		}
+	}
+
+	var (
+		serviceGUID string
+		serviceName string
+	)
+
+	if installConfig.Config.PowerVS.ServiceInstanceGUID == "" {
+		serviceName = fmt.Sprintf("%s-power-iaas", clusterID.InfraID)
+	} else {
+		serviceGUID = installConfig.Config.PowerVS.ServiceInstanceGUID
+	}
+
+	powervsConfig, err := powervsmanifests.CloudProviderConfig(
+		clusterID.InfraID,
+		accountID,
+		vpc,
+		vpcRegion,
+		installConfig.Config.Platform.PowerVS.PowerVSResourceGroup,
+		vpcSubnets,
+		serviceGUID,
+		serviceName,
+		installConfig.Config.PowerVS.Region,
+		installConfig.Config.PowerVS.Zone,
+	)
+	if err != nil {
+		return errors.Wrap(err, "could not create cloud provider config")
+	}
+	cm.Data[ConfigDataKey] = powervsConfig
+	return nil
+}
+
+// generateVSphereCloudProviderConfig fills the cloud provider config for vSphere.
+func generateVSphereCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig, clusterID := req.installConfig, req.clusterID
+
+	var vsphereConfig string
+	var err error
+	// When we GA multi vcenter, we should only support yaml generation here.
+	if installConfig.Config.EnabledFeatureGates().Enabled(features.FeatureGateVSphereMultiVCenters) {
+		vsphereConfig, err = vspheremanifests.CloudProviderConfigYaml(clusterID.InfraID, installConfig.Config.Platform.VSphere, installConfig.Config.Platform.VSphere.NodeNetwork)
+	} else {
+		vsphereConfig, err = vspheremanifests.CloudProviderConfigIni(clusterID.InfraID, installConfig.Config.Platform.VSphere, installConfig.Config.Platform.VSphere.NodeNetwork)
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "could not create cloud provider config")
+	}
+	cm.Data[ConfigDataKey] = vsphereConfig
+
+	// The external cloud controller manager reads the yaml config, which is
+	// added next to the ini one while clusters transition to it.
+	if externalCloudProviderPreviewEnabled(installConfig.Config) && !installConfig.Config.EnabledFeatureGates().Enabled(features.FeatureGateVSphereMultiVCenters) {
+		externalConfig, err := vspheremanifests.CloudProviderConfigYaml(clusterID.InfraID, installConfig.Config.Platform.VSphere, installConfig.Config.Platform.VSphere.NodeNetwork)
+		if err != nil {
+			return errors.Wrap(err, "could not create external cloud provider config")
+		}
+		cm.Data[ExternalConfigDataKey] = externalConfig
+	}
+	return nil
+}
+
+// generateNutanixCloudProviderConfig fills the cloud provider config for Nutanix.
+func generateNutanixCloudProviderConfig(ctx context.Context, req *cloudProviderConfigRequest, cm *corev1.ConfigMap) error {
+	installConfig := req.installConfig
+
+	configJSON, err := nutanixmanifests.CloudConfigJSON(installConfig.Config.Nutanix)
+	if err != nil {
+		return errors.Wrap(err, "could not create Nutanix Cloud provider config")
+	}
+	cm.Data[ConfigDataKey] = configJSON
+	return nil
+}
+
+// fillExternalCloudProviderConfig fills the cloud provider config of the
+// external platform when it names its external cloud provider, whose config is
+// passed through as is. The external platform has no generator, since the
+// ConfigMap is left empty otherwise.
+func fillExternalCloudProviderConfig(ic *types.InstallConfig, cm *corev1.ConfigMap) {
+	if !hasExternalCloudProvider(ic) {
+		return
+	}
+	cm.Data[ProviderDataKey] = ic.External.CloudProviderName
+	if ic.External.CloudProviderConfig != "" {
+		cm.Data[ConfigDataKey] = ic.External.CloudProviderConfig
+	}
+}
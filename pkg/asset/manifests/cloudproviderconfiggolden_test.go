@@ -0,0 +1,109 @@
+package manifests
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	icibmcloud "github.com/openshift/installer/pkg/asset/installconfig/ibmcloud"
+	"github.com/openshift/installer/pkg/types"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+)
+
+// updateGoldens rewrites the golden cloud provider configs with the generated
+// ones, e.g., go test ./pkg/asset/manifests -run TestCloudProviderConfigGolden -update.
+var updateGoldens = flag.Bool("update", false, "update the golden files of the tests")
+
+// TestCloudProviderConfigGolden generates the cloud provider config manifest of
+// a representative install config of each platform and compares it to the
+// golden file of the platform, to catch changes to the generated config. The
+// lookups of the platforms are replaced by fakes so that the manifests only
+// depend on the install configs.
+func TestCloudProviderConfigGolden(t *testing.T) {
+	cases := []struct {
+		name          string
+		installConfig *types.InstallConfig
+		// withMetadata sets the metadata of the platform on the install config asset.
+		withMetadata bool
+		opts         []CloudProviderConfigOption
+	}{{
+		name:          "aws-c2s",
+		installConfig: icBuild.build(icBuild.withAWSRegion("us-iso-east-1"), icBuild.withAdditionalTrustBundle(testTrustBundle)),
+	}, {
+		name: "azure",
+		installConfig: icBuild.build(icBuild.forAzure(), func(ic *types.InstallConfig) {
+			ic.Platform.Azure.CloudName = azuretypes.PublicCloud
+			ic.Platform.Azure.Region = "eastus"
+			ic.Platform.Azure.UserTags = map[string]string{"team": "test", "env": "ci", "owner": "test-owner"}
+		}),
+		opts: []CloudProviderConfigOption{WithAzureSession(testAzureSession)},
+	}, {
+		name:          "gcp",
+		installConfig: icBuild.build(icBuild.withGCPProjectID("test-project"), icBuild.withAdditionalTrustBundle(testTrustBundle)),
+	}, {
+		name: "ibmcloud",
+		installConfig: icBuild.build(func(ic *types.InstallConfig) {
+			ic.Platform.IBMCloud = &ibmcloudtypes.Platform{
+				Region:            "us-south",
+				ResourceGroupName: "test-resource-group",
+				DefaultMachinePlatform: &ibmcloudtypes.MachinePool{
+					Zones: []string{"us-south-1", "us-south-2", "us-south-3"},
+				},
+			}
+			ic.ControlPlane = &types.MachinePool{Name: types.MachinePoolControlPlaneRoleName}
+			ic.Compute = []types.MachinePool{{Name: types.MachinePoolComputeRoleName}}
+		}),
+		withMetadata: true,
+		opts:         []CloudProviderConfigOption{withIBMCloudAccountIDResolver(&fakeAccountIDResolver{accountID: "test-account-id"})},
+	}, {
+		name:          "vsphere",
+		installConfig: icBuild.build(icBuild.forVSphere()),
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			generate := func() []byte {
+				cpc, parents := newTestCloudProviderConfig(tc.installConfig, nil, tc.opts...)
+				if tc.withMetadata {
+					installConfigAsset := installconfig.MakeAsset(tc.installConfig)
+					installConfigAsset.IBMCloud = icibmcloud.NewMetadata(tc.installConfig)
+					parents.Add(installConfigAsset)
+				}
+				if !assert.NoError(t, cpc.Generate(context.Background(), parents), "failed to generate asset") {
+					return nil
+				}
+				files := cpc.Files()
+				if !assert.Len(t, files, 1, "expected one manifest") {
+					return nil
+				}
+				return files[0].Data
+			}
+			data := generate()
+			if data == nil {
+				return
+			}
+			// The manifest must not change between generations, or the goldens
+			// would be flaky.
+			assert.Equal(t, string(data), string(generate()), "the manifest is not deterministic")
+
+			golden := filepath.Join("testdata", "cloudproviderconfig", tc.name+".yaml")
+			if *updateGoldens {
+				if !assert.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755)) {
+					return
+				}
+				assert.NoError(t, os.WriteFile(golden, data, 0o644)) //nolint:gosec // the golden files are checked in
+				return
+			}
+			expected, err := os.ReadFile(golden)
+			if !assert.NoError(t, err, "failed to read the golden file, run the test with -update to create it") {
+				return
+			}
+			assert.Equal(t, string(expected), string(data), "the manifest differs from %s, run the test with -update if the change is expected", golden)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package manifests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"sort"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/installer/pkg/asset"
+)
+
+// cloudProviderConfigOverridesFileName is kept out of the manifests directory
+// so that the overrides are not applied to the cluster as a manifest.
+const cloudProviderConfigOverridesFileName = "cloud-provider-config-overrides.yaml"
+
+// CloudProviderConfigOverrides holds the optional overrides of the cloud
+// provider config, read from cloud-provider-config-overrides.yaml. The file
+// maps keys of the cloud provider config data to the values replacing the
+// generated ones. A value which is a mapping, or a JSON object, is merged
+// into a generated JSON config instead of replacing it.
+type CloudProviderConfigOverrides struct {
+	File *asset.File
+	Data map[string]interface{}
+}
+
+var _ asset.WritableAsset = (*CloudProviderConfigOverrides)(nil)
+
+// Name returns a human friendly name for the asset.
+func (*CloudProviderConfigOverrides) Name() string {
+	return "Cloud Provider Config Overrides"
+}
+
+// Dependencies returns all of the dependencies directly needed to generate
+// the asset.
+func (*CloudProviderConfigOverrides) Dependencies() []asset.Asset {
+	return []asset.Asset{}
+}
+
+// Generate generates no overrides, they can only be provided on disk.
+func (o *CloudProviderConfigOverrides) Generate(_ context.Context, _ asset.Parents) error {
+	return nil
+}
+
+// Files returns no files, so that the overrides maintained by the user are
+// neither rewritten nor removed from disk.
+func (o *CloudProviderConfigOverrides) Files() []*asset.File {
+	return []*asset.File{}
+}
+
+// Load returns the overrides from disk. A missing file is not an error.
+func (o *CloudProviderConfigOverrides) Load(f asset.FileFetcher) (bool, error) {
+	file, err := f.FetchByName(cloudProviderConfigOverridesFileName)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to load %s", cloudProviderConfigOverridesFileName)
+	}
+
+	data := map[string]interface{}{}
+	if err := yaml.Unmarshal(file.Data, &data); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal %s", cloudProviderConfigOverridesFileName)
+	}
+	o.File, o.Data = file, data
+	return true, nil
+}
+
+// applyCloudProviderConfigOverrides applies the overrides over the data of the
+// cloud provider config. Overrides of JSON configs are merged field by field,
+// with the override taking precedence; other values are replaced as a whole.
+func applyCloudProviderConfigOverrides(data map[string]string, overrides map[string]interface{}) error {
+	keys := make([]string, 0, len(overrides))
+	for k := range overrides {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		override := overrides[key]
+		current, generated := data[key]
+		currentObject, currentIsObject := parseJSONObject(current)
+
+		var overrideObject map[string]interface{}
+		switch v := override.(type) {
+		case string:
+			var overrideIsObject bool
+			overrideObject, overrideIsObject = parseJSONObject(v)
+			if !generated || !currentIsObject || !overrideIsObject {
+				data[key] = v
+				continue
+			}
+		case map[string]interface{}:
+			overrideObject = v
+			if generated && !currentIsObject {
+				return errors.Errorf("cannot merge the override of %s, the generated value is not a JSON object", key)
+			}
+		default:
+			return errors.Errorf("override of %s must be a string or a mapping", key)
+		}
+
+		merged, err := encodeJSONConfig(mergeJSONObjects(currentObject, overrideObject))
+		if err != nil {
+			return errors.Wrapf(err, "failed to merge the override of %s", key)
+		}
+		data[key] = merged
+	}
+	return nil
+}
+
+func parseJSONObject(value string) (map[string]interface{}, bool) {
+	object := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(value), &object); err != nil {
+		return nil, false
+	}
+	return object, true
+}
+
+// mergeJSONObjects merges src into dst, recursing into the objects present in
+// both.
+func mergeJSONObjects(dst, src map[string]interface{}) map[string]interface{} {
+	if dst == nil {
+		dst = map[string]interface{}{}
+	}
+	for k, srcValue := range src {
+		srcObject, srcIsObject := srcValue.(map[string]interface{})
+		dstObject, dstIsObject := dst[k].(map[string]interface{})
+		if srcIsObject && dstIsObject {
+			dst[k] = mergeJSONObjects(dstObject, srcObject)
+			continue
+		}
+		dst[k] = srcValue
+	}
+	return dst
+}
+
+// encodeJSONConfig encodes the config the same way the generated JSON configs are.
+func encodeJSONConfig(config map[string]interface{}) (string, error) {
+	buff := &bytes.Buffer{}
+	encoder := json.NewEncoder(buff)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(config); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
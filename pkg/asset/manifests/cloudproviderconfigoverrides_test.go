@@ -0,0 +1,170 @@
+package manifests
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/asset"
+	"github.com/openshift/installer/pkg/asset/installconfig"
+	"github.com/openshift/installer/pkg/asset/mock"
+	"github.com/openshift/installer/pkg/types"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+)
+
+func TestCloudProviderConfigOverridesLoad(t *testing.T) {
+	cases := []struct {
+		name          string
+		data          string
+		fetchError    error
+		expectedFound bool
+		expectedError string
+		expectedData  map[string]interface{}
+	}{{
+		name:       "missing",
+		fetchError: &os.PathError{Err: os.ErrNotExist},
+	}, {
+		name: "valid",
+		data: `config:
+  loadBalancerSku: basic
+ca-bundle.pem: test-bundle
+`,
+		expectedFound: true,
+		expectedData: map[string]interface{}{
+			"config":        map[string]interface{}{"loadBalancerSku": "basic"},
+			"ca-bundle.pem": "test-bundle",
+		},
+	}, {
+		name:          "invalid",
+		data:          "- config",
+		expectedError: `^failed to unmarshal cloud-provider-config-overrides.yaml: `,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			fileFetcher := mock.NewMockFileFetcher(mockCtrl)
+			fileFetcher.EXPECT().FetchByName(cloudProviderConfigOverridesFileName).
+				Return(
+					&asset.File{
+						Filename: cloudProviderConfigOverridesFileName,
+						Data:     []byte(tc.data)},
+					tc.fetchError,
+				)
+
+			overrides := &CloudProviderConfigOverrides{}
+			found, err := overrides.Load(fileFetcher)
+			assert.Equal(t, tc.expectedFound, found, "unexpected found value returned from Load")
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err, "unexpected error from Load")
+			assert.Equal(t, tc.expectedData, overrides.Data)
+		})
+	}
+}
+
+func TestApplyCloudProviderConfigOverrides(t *testing.T) {
+	cases := []struct {
+		name          string
+		data          map[string]string
+		overrides     map[string]interface{}
+		expectedData  map[string]string
+		expectedError string
+	}{{
+		name:         "no overrides",
+		data:         map[string]string{"config": "[Global]\n"},
+		expectedData: map[string]string{"config": "[Global]\n"},
+	}, {
+		name:         "replace non-JSON config",
+		data:         map[string]string{"config": "[Global]\n"},
+		overrides:    map[string]interface{}{"config": "[Global]\nsecret-name = test\n"},
+		expectedData: map[string]string{"config": "[Global]\nsecret-name = test\n"},
+	}, {
+		name:      "merge JSON config",
+		data:      map[string]string{"config": `{"cloud": "AzurePublicCloud", "loadBalancerSku": "standard", "nested": {"a": 1, "b": 2}}`},
+		overrides: map[string]interface{}{"config": map[string]interface{}{"loadBalancerSku": "basic", "nested": map[string]interface{}{"b": 3}}},
+		expectedData: map[string]string{"config": `{
+	"cloud": "AzurePublicCloud",
+	"loadBalancerSku": "basic",
+	"nested": {
+		"a": 1,
+		"b": 3
+	}
+}
+`},
+	}, {
+		name:      "merge JSON string",
+		data:      map[string]string{"config": `{"cloud": "AzurePublicCloud", "vmType": "standard"}`},
+		overrides: map[string]interface{}{"config": `{"vmType": "vmss"}`},
+		expectedData: map[string]string{"config": `{
+	"cloud": "AzurePublicCloud",
+	"vmType": "vmss"
+}
+`},
+	}, {
+		name:      "add key",
+		data:      map[string]string{"config": "[Global]\n"},
+		overrides: map[string]interface{}{"ca-bundle.pem": "test-bundle"},
+		expectedData: map[string]string{
+			"config":        "[Global]\n",
+			"ca-bundle.pem": "test-bundle",
+		},
+	}, {
+		name:          "structured override of non-JSON config",
+		data:          map[string]string{"config": "[Global]\n"},
+		overrides:     map[string]interface{}{"config": map[string]interface{}{"a": "b"}},
+		expectedError: `^cannot merge the override of config, the generated value is not a JSON object$`,
+	}, {
+		name:          "unsupported override",
+		data:          map[string]string{"config": "[Global]\n"},
+		overrides:     map[string]interface{}{"config": []interface{}{"a"}},
+		expectedError: `^override of config must be a string or a mapping$`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := applyCloudProviderConfigOverrides(tc.data, tc.overrides)
+			if tc.expectedError != "" {
+				assert.Regexp(t, tc.expectedError, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedData, tc.data)
+		})
+	}
+}
+
+func TestCloudProviderConfigGenerateWithOverrides(t *testing.T) {
+	installConfig := icBuild.build(icBuild.forAzure(), func(ic *types.InstallConfig) {
+		ic.Platform.Azure.CloudName = azuretypes.PublicCloud
+		ic.Platform.Azure.Region = "eastus"
+	})
+	parents := asset.Parents{}
+	parents.Add(
+		&installconfig.ClusterID{
+			UUID:    "test-uuid",
+			InfraID: "test-infra-id",
+		},
+		installconfig.MakeAsset(installConfig),
+		&CloudProviderConfigOverrides{
+			Data: map[string]interface{}{
+				"config": map[string]interface{}{"cloudProviderRateLimit": true},
+			},
+		},
+		&CloudProviderConfigCABundle{},
+	)
+
+	cpc := NewCloudProviderConfig(WithAzureSession(testAzureSession))
+	if !assert.NoError(t, cpc.Generate(context.Background(), parents), "failed to generate asset") {
+		return
+	}
+	config := cpc.ConfigMap.Data[ConfigDataKey]
+	assert.Contains(t, config, `"cloudProviderRateLimit": true`)
+	assert.Contains(t, config, `"resourceGroup": "test-infra-id-rg"`)
+	assert.Contains(t, string(cpc.File.Data), "cloudProviderRateLimit")
+}
@@ -0,0 +1,92 @@
+package manifests
+
+import (
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/types"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+	powervstypes "github.com/openshift/installer/pkg/types/powervs"
+)
+
+// RemoteDependencies returns the cloud services which Generate contacts to
+// build the cloud provider config for the install config, so that the access
+// to them can be checked or allowed beforehand, e.g. in restricted networks.
+// The endpoint of a service is included when the install config overrides it.
+// It is empty when the cloud provider config is computed from the install
+// config only, or when it is generated offline.
+func (cpc *CloudProviderConfig) RemoteDependencies(ic *types.InstallConfig) []string {
+	if ic == nil {
+		return nil
+	}
+	options := cpc.resolveOptions()
+	offline := cloudProviderConfigOffline()
+
+	deps := []string{}
+	switch ic.Platform.Name() {
+	case azuretypes.Name:
+		// The session is only created when none is given.
+		if options.azureSession == nil && !offline {
+			deps = append(deps, "Azure Active Directory")
+		}
+	case ibmcloudtypes.Name:
+		if offline {
+			break
+		}
+		deps = append(deps, remoteDependency("IBM Cloud IAM", ibmcloudServiceEndpoint(ic.IBMCloud, configv1.IBMCloudServiceIAM)))
+		if ibmcloudNeedsVPC(ic) {
+			deps = append(deps, remoteDependency("IBM Cloud VPC", ibmcloudServiceEndpoint(ic.IBMCloud, configv1.IBMCloudServiceVPC)))
+		}
+	case openstacktypes.Name:
+		deps = append(deps, "OpenStack Identity (Keystone)")
+		if ic.OpenStack.ExternalNetwork != "" {
+			deps = append(deps, "OpenStack Networking (Neutron)")
+		}
+	case powervstypes.Name:
+		deps = append(deps, "IBM Cloud IAM")
+		if ic.PowerVS.VPCName != "" {
+			deps = append(deps, "IBM Cloud VPC")
+		}
+	}
+	return deps
+}
+
+// remoteDependency returns the name of the service along with its endpoint,
+// when set.
+func remoteDependency(service, endpoint string) string {
+	if endpoint == "" {
+		return service
+	}
+	return service + " (" + endpoint + ")"
+}
+
+// ibmcloudServiceEndpoint returns the endpoint override of the IBM Cloud
+// service, or an empty string when the default endpoint is used.
+func ibmcloudServiceEndpoint(p *ibmcloudtypes.Platform, name configv1.IBMCloudServiceName) string {
+	for _, e := range p.ServiceEndpoints {
+		if e.Name == name {
+			return e.URL
+		}
+	}
+	return ""
+}
+
+// ibmcloudNeedsVPC returns whether the IBM Cloud VPC API is called, to look up
+// the existing subnets or the zones of the region when the machine pools do
+// not set them.
+func ibmcloudNeedsVPC(ic *types.InstallConfig) bool {
+	if len(ic.IBMCloud.ControlPlaneSubnets) > 0 || len(ic.IBMCloud.ComputeSubnets) > 0 {
+		return true
+	}
+	controlPlane := &ibmcloudtypes.MachinePool{}
+	controlPlane.Set(ic.IBMCloud.DefaultMachinePlatform)
+	if ic.ControlPlane != nil {
+		controlPlane.Set(ic.ControlPlane.Platform.IBMCloud)
+	}
+	compute := &ibmcloudtypes.MachinePool{}
+	compute.Set(ic.IBMCloud.DefaultMachinePlatform)
+	if worker := ic.WorkerMachinePool(); worker != nil {
+		compute.Set(worker.Platform.IBMCloud)
+	}
+	return len(controlPlane.Zones) == 0 || len(compute.Zones) == 0
+}
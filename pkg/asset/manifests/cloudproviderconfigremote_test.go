@@ -0,0 +1,80 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/installer/pkg/types"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
+)
+
+func TestCloudProviderConfigRemoteDependencies(t *testing.T) {
+	cases := []struct {
+		name     string
+		platform types.Platform
+		offline  bool
+		expected []string
+	}{{
+		name:     "aws",
+		platform: icBuild.build(icBuild.forAWS()).Platform,
+		expected: []string{},
+	}, {
+		name:     "azure",
+		platform: types.Platform{Azure: &azuretypes.Platform{}},
+		expected: []string{"Azure Active Directory"},
+	}, {
+		name: "azure stack with existing vnet",
+		platform: types.Platform{Azure: &azuretypes.Platform{
+			CloudName:      azuretypes.StackCloud,
+			ARMEndpoint:    "https://management.local.azurestack.external",
+			VirtualNetwork: "vnet",
+			ComputeSubnet:  "compute",
+		}},
+		expected: []string{"Azure Active Directory"},
+	}, {
+		name:     "azure offline",
+		platform: types.Platform{Azure: &azuretypes.Platform{VirtualNetwork: "vnet", ComputeSubnet: "compute"}},
+		offline:  true,
+		expected: []string{},
+	}, {
+		name: "gcp with compute endpoint",
+		platform: types.Platform{GCP: &gcptypes.Platform{ServiceEndpoints: []gcptypes.ServiceEndpoint{{
+			Name: gcptypes.ComputeServiceEndpoint,
+			URL:  "https://compute.example.com",
+		}}}},
+		expected: []string{},
+	}, {
+		name: "ibmcloud with zones",
+		platform: types.Platform{IBMCloud: &ibmcloudtypes.Platform{
+			DefaultMachinePlatform: &ibmcloudtypes.MachinePool{Zones: []string{"us-south-1"}},
+			ServiceEndpoints: []configv1.IBMCloudServiceEndpoint{{
+				Name: configv1.IBMCloudServiceIAM,
+				URL:  "https://iam.example.com",
+			}},
+		}},
+		expected: []string{"IBM Cloud IAM (https://iam.example.com)"},
+	}, {
+		name:     "ibmcloud without zones",
+		platform: types.Platform{IBMCloud: &ibmcloudtypes.Platform{}},
+		expected: []string{"IBM Cloud IAM", "IBM Cloud VPC"},
+	}, {
+		name:     "openstack with external network",
+		platform: types.Platform{OpenStack: &openstacktypes.Platform{ExternalNetwork: "external"}},
+		offline:  true,
+		expected: []string{"OpenStack Identity (Keystone)", "OpenStack Networking (Neutron)"},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.offline {
+				t.Setenv("OPENSHIFT_INSTALL_OFFLINE_CLOUD_PROVIDER_CONFIG", "1")
+			}
+			ic := &types.InstallConfig{Platform: tc.platform}
+			assert.Equal(t, tc.expected, (&CloudProviderConfig{}).RemoteDependencies(ic))
+		})
+	}
+}
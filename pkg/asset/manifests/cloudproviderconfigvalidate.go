@@ -0,0 +1,72 @@
+package manifests
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/types"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+// ValidateCloudProviderConfigMap checks a cloud provider config ConfigMap of
+// the platform against the rules the installer checks the ones it generates
+// and loads with, e.g. for admission webhooks validating the ConfigMaps users
+// submit. The data must only hold known keys, the config must parse in the
+// format the cloud provider of the platform reads, and the CA bundle must only
+// hold PEM encoded certificates, and be there when the config reads it. Unlike
+// generating the config, it does not reach the cloud.
+func ValidateCloudProviderConfigMap(cm *corev1.ConfigMap, platformName string) error {
+	if cm == nil {
+		return errors.New("no cloud provider config ConfigMap to validate")
+	}
+	if !sets.New(types.PlatformNames...).Insert(types.HiddenPlatformNames...).Has(platformName) {
+		return errors.Errorf("unknown platform %q", platformName)
+	}
+
+	if err := validateCloudProviderConfigDataKeys(cm.Data); err != nil {
+		return err
+	}
+	if config, ok := cm.Data[ConfigDataKey]; ok {
+		if err := validatePlatformCloudProviderConfigFormat(platformName, config); err != nil {
+			return err
+		}
+	}
+	if err := validateCloudProviderConfigCABundle(cm.Data); err != nil {
+		return err
+	}
+	if err := validateCloudProviderConfigCABundleReference(cm.Data); err != nil {
+		return err
+	}
+	return validateCloudProviderConfigDataSize(cm.Data)
+}
+
+// validatePlatformCloudProviderConfigFormat checks that the config parses in
+// one of the formats the cloud provider of the platform may read, which for
+// vSphere depends on the feature gates of the install config.
+func validatePlatformCloudProviderConfigFormat(platformName, config string) error {
+	formatOf, ok := cloudProviderConfigFormats[platformName]
+	if !ok {
+		return nil
+	}
+	formats := []cloudProviderConfigFormat{formatOf(&types.InstallConfig{})}
+	if platformName == vspheretypes.Name {
+		formats = []cloudProviderConfigFormat{iniCloudProviderConfigFormat, yamlCloudProviderConfigFormat}
+	}
+
+	var failures []string
+	for _, format := range formats {
+		err := parseCloudProviderConfigFormat(format, config)
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, err.Error())
+	}
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, string(format))
+	}
+	return errors.Errorf("the %s cloud provider config is not valid %s: %s", platformName, strings.Join(names, " or "), strings.Join(failures, "; "))
+}
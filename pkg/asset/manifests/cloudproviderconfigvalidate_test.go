@@ -0,0 +1,82 @@
+package manifests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	awstypes "github.com/openshift/installer/pkg/types/aws"
+	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	nonetypes "github.com/openshift/installer/pkg/types/none"
+	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestValidateCloudProviderConfigMap(t *testing.T) {
+	cases := []struct {
+		name          string
+		platformName  string
+		data          map[string]string
+		expectedError string
+	}{{
+		name:         "aws",
+		platformName: awstypes.Name,
+		data:         map[string]string{ConfigDataKey: "[Global]\n", CABundleDataKey: testTrustBundle},
+	}, {
+		name:         "azure",
+		platformName: azuretypes.Name,
+		data:         map[string]string{ConfigDataKey: "{\n\t\"cloud\": \"AzurePublicCloud\"\n}\n"},
+	}, {
+		name:         "vsphere ini",
+		platformName: vspheretypes.Name,
+		data:         map[string]string{ConfigDataKey: "[Global]\nsecret-name = \"vsphere-creds\"\n"},
+	}, {
+		name:         "vsphere yaml",
+		platformName: vspheretypes.Name,
+		data:         map[string]string{ConfigDataKey: "global:\n  secretName: vsphere-creds\n"},
+	}, {
+		name:         "none",
+		platformName: nonetypes.Name,
+		data:         map[string]string{ConfigDataKey: "anything"},
+	}, {
+		name:          "unknown platform",
+		platformName:  "unknown",
+		data:          map[string]string{ConfigDataKey: "[Global]\n"},
+		expectedError: `unknown platform "unknown"`,
+	}, {
+		name:          "unknown key",
+		platformName:  awstypes.Name,
+		data:          map[string]string{ConfigDataKey: "[Global]\n", "cofnig": "[Global]\n"},
+		expectedError: "unknown data keys cofnig in the cloud provider config, the known keys are ca-bundle.pem, config, endpoints, external-config, provider",
+	}, {
+		name:          "invalid format",
+		platformName:  azuretypes.Name,
+		data:          map[string]string{ConfigDataKey: "[Global]\n"},
+		expectedError: "the azure cloud provider config is not valid JSON: invalid character 'G' looking for beginning of value",
+	}, {
+		name:          "invalid CA bundle",
+		platformName:  awstypes.Name,
+		data:          map[string]string{ConfigDataKey: "[Global]\n", CABundleDataKey: "not a certificate"},
+		expectedError: "invalid ca-bundle.pem in the cloud provider config, parsed 0 of 0 PEM blocks: trailing data is not PEM encoded",
+	}, {
+		name:          "missing CA bundle",
+		platformName:  awstypes.Name,
+		data:          map[string]string{ConfigDataKey: "[Global]\nCABundle = " + cloudProviderConfigCABundlePath + "\n"},
+		expectedError: "the config reads the CA bundle from " + cloudProviderConfigCABundlePath + ", but the cloud provider config has no " + CABundleDataKey,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCloudProviderConfigMap(&corev1.ConfigMap{Data: tc.data}, tc.platformName)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestValidateCloudProviderConfigMapVSphereInvalid(t *testing.T) {
+	err := ValidateCloudProviderConfigMap(&corev1.ConfigMap{Data: map[string]string{ConfigDataKey: "[Global"}}, vspheretypes.Name)
+	assert.ErrorContains(t, err, "the vsphere cloud provider config is not valid INI or YAML")
+}
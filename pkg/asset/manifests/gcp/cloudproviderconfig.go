@@ -4,6 +4,13 @@ import (
 	"bytes"
 	"fmt"
 	"text/template"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
 )
 
 // https://github.com/kubernetes/kubernetes/blob/368ee4bb8ee7a0c18431cd87ee49f0c890aa53e5/staging/src/k8s.io/legacy-cloud-providers/gce/gce.go#L188
@@ -14,23 +21,65 @@ type config struct {
 type global struct {
 	ProjectID string `gcfg:"project-id"`
 
-	Regional  bool `gcfg:"regional"`
-	Multizone bool `gcfg:"multizone"`
+	Regional  bool   `gcfg:"regional"`
+	Multizone bool   `gcfg:"multizone"`
+	LocalZone string `gcfg:"local-zone"`
 
 	NodeTags                     []string `gcfg:"node-tags"`
 	NodeInstancePrefix           string   `gcfg:"node-instance-prefix"`
 	ExternalInstanceGroupsPrefix string   `gcfg:"external-instance-groups-prefix"`
 
 	SubnetworkName string `gcfg:"subnetwork-name"`
+	NetworkName    string `gcfg:"network-name"`
 
 	NetworkProjectID string `gcfg:"network-project-id"`
+
+	APIEndpoint          string `gcfg:"api-endpoint"`
+	ContainerAPIEndpoint string `gcfg:"container-api-endpoint"`
+
+	TokenURL string `gcfg:"token-url"`
+
+	StackType string `gcfg:"stack-type"`
 }
 
-// CloudProviderConfig generates the cloud provider config for the GCP platform.
-func CloudProviderConfig(infraID, projectID, subnet, networkProjectID string) (string, error) {
+// applicationDefaultCredentialsTokenURL makes the cloud provider use the application
+// default credentials instead of looking for a service account key, see the
+// handling of token-url in the legacy GCE cloud provider.
+const applicationDefaultCredentialsTokenURL = "nil"
+
+// dualStackType makes the cloud provider assign both the IPv4 and the IPv6
+// addresses of the nodes.
+const dualStackType = "IPV4_IPV6"
+
+// CloudProviderConfig holds the parameters of the cloud provider config for the
+// GCP platform.
+type CloudProviderConfig struct {
+	InfraID          string
+	ProjectID        string
+	SubnetName       string
+	NetworkProjectID string
+	ServiceEndpoints []gcptypes.ServiceEndpoint
+	CredentialsMode  types.CredentialsMode
+	// Zone is the only zone of the machines of single-zone clusters, and empty
+	// for clusters spread across the zones of the region.
+	Zone string
+	// DualStack sets the stack type, so that the nodes get both IPv4 and IPv6
+	// addresses.
+	DualStack bool
+	// NodeInstancePrefix overrides the infrastructure ID as the prefix of the
+	// names of the instances of the nodes when set.
+	NodeInstancePrefix string
+	// NetworkName is the name of the existing VPC network of the cluster, and
+	// empty when the installer creates the network, whose name the cloud
+	// provider derives from the infrastructure ID.
+	NetworkName string
+}
+
+// INI generates the cloud provider config for the GCP platform.
+func (params CloudProviderConfig) INI() (string, error) {
 	config := &config{
 		Global: global{
-			ProjectID: projectID,
+			ProjectID: params.ProjectID,
 
 			// To make sure k8s cloud provider is looking for instances in all zones.
 			Regional:  true,
@@ -38,18 +87,54 @@ func CloudProviderConfig(infraID, projectID, subnet, networkProjectID string) (s
 
 			// To make sure k8s cloud provider has tags for firewall for load balancer.
 			// The CAPI gcp provider uses the node tag "control-plane" for master nodes.
-			NodeTags:                     []string{fmt.Sprintf("%s-master", infraID), fmt.Sprintf("%s-control-plane", infraID), fmt.Sprintf("%s-worker", infraID)},
-			NodeInstancePrefix:           infraID,
-			ExternalInstanceGroupsPrefix: infraID,
+			NodeTags:                     []string{fmt.Sprintf("%s-master", params.InfraID), fmt.Sprintf("%s-control-plane", params.InfraID), fmt.Sprintf("%s-worker", params.InfraID)},
+			NodeInstancePrefix:           params.InfraID,
+			ExternalInstanceGroupsPrefix: params.InfraID,
 
 			// Used for internal load balancers
-			SubnetworkName: subnet,
+			SubnetworkName: params.SubnetName,
+			NetworkName:    params.NetworkName,
 
 			// Used for shared vpc installations,
-			NetworkProjectID: networkProjectID,
+			NetworkProjectID: params.NetworkProjectID,
 		},
 	}
 
+	// The instances of single-zone clusters are only looked for in their zone.
+	if params.Zone != "" {
+		config.Global.Regional = false
+		config.Global.Multizone = false
+		config.Global.LocalZone = params.Zone
+	}
+
+	// The cloud provider matches the nodes with the instances by the prefix of
+	// their names.
+	if params.NodeInstancePrefix != "" {
+		config.Global.NodeInstancePrefix = params.NodeInstancePrefix
+	}
+
+	if params.DualStack {
+		config.Global.StackType = dualStackType
+	}
+
+	// In manual mode, the credentials are short-lived tokens, e.g. from workload identity,
+	// so there is no service account key for the cloud provider to use.
+	if params.CredentialsMode == types.ManualCredentialsMode {
+		config.Global.TokenURL = applicationDefaultCredentialsTokenURL
+	}
+
+	// Add any GCP Service Endpoint overrides as necessary, the public endpoints are used otherwise.
+	for _, endpoint := range params.ServiceEndpoints {
+		switch endpoint.Name {
+		case gcptypes.ComputeServiceEndpoint:
+			config.Global.APIEndpoint = endpoint.URL
+		case gcptypes.ContainerServiceEndpoint:
+			config.Global.ContainerAPIEndpoint = endpoint.URL
+		default:
+			logrus.Debugf("ignoring unnecessary endpoint override for cloud provider config: %s", endpoint.Name)
+		}
+	}
+
 	buf := &bytes.Buffer{}
 	template := template.Must(template.New("gce cloudproviderconfig").Parse(configTmpl))
 	if err := template.Execute(buf, config); err != nil {
@@ -58,16 +143,87 @@ func CloudProviderConfig(infraID, projectID, subnet, networkProjectID string) (s
 	return buf.String(), nil
 }
 
+// HasCloudProviderServiceEndpoints returns whether any of the service endpoints
+// overrides an endpoint used by the cloud provider.
+func HasCloudProviderServiceEndpoints(serviceEndpoints []gcptypes.ServiceEndpoint) bool {
+	for _, endpoint := range serviceEndpoints {
+		switch endpoint.Name {
+		case gcptypes.ComputeServiceEndpoint, gcptypes.ContainerServiceEndpoint:
+			return true
+		}
+	}
+	return false
+}
+
+// SingleZone returns the zone of the machine pools when they are all in the same
+// zone, and an empty string when they are spread across the zones of the region.
+func SingleZone(ic *types.InstallConfig) string {
+	zones := configuredZones(ic)
+	if zones.Len() != 1 {
+		return ""
+	}
+	return sets.List(zones)[0]
+}
+
+// IsDualStack returns whether the networks of the install config have both
+// IPv4 and IPv6 ranges.
+func IsDualStack(n *types.Networking) bool {
+	if n == nil {
+		return false
+	}
+	var hasIPv4, hasIPv6 bool
+	check := func(cidr ipnet.IPNet) {
+		if cidr.IP.To4() != nil {
+			hasIPv4 = true
+		} else {
+			hasIPv6 = true
+		}
+	}
+	for _, network := range n.MachineNetwork {
+		check(network.CIDR)
+	}
+	for _, network := range n.ClusterNetwork {
+		check(network.CIDR)
+	}
+	for _, network := range n.ServiceNetwork {
+		check(network)
+	}
+	return hasIPv4 && hasIPv6
+}
+
+// configuredZones returns the zones set in the machine pools of the install config.
+func configuredZones(ic *types.InstallConfig) sets.Set[string] {
+	zones := sets.New[string]()
+	if ic.Platform.GCP.DefaultMachinePlatform != nil {
+		zones.Insert(ic.Platform.GCP.DefaultMachinePlatform.Zones...)
+	}
+	if ic.ControlPlane != nil && ic.ControlPlane.Platform.GCP != nil {
+		zones.Insert(ic.ControlPlane.Platform.GCP.Zones...)
+	}
+	for _, compute := range ic.Compute {
+		if compute.Platform.GCP != nil {
+			zones.Insert(compute.Platform.GCP.Zones...)
+		}
+	}
+	return zones
+}
+
 var configTmpl = `[global]
 project-id      = {{.Global.ProjectID}}
 regional        = {{.Global.Regional}}
 multizone       = {{.Global.Multizone}}
+{{ if ne .Global.LocalZone "" }}local-zone      = {{.Global.LocalZone}}
+{{ end -}}
 {{range $idx, $tag := .Global.NodeTags -}}
 node-tags       = {{$tag}}
 {{end -}}
 node-instance-prefix = {{.Global.NodeInstancePrefix}}
 external-instance-groups-prefix = {{.Global.ExternalInstanceGroupsPrefix}}
 subnetwork-name = {{.Global.SubnetworkName}}
+{{ if ne .Global.NetworkName "" }}network-name = {{.Global.NetworkName}}
+{{ end -}}
+{{ if ne .Global.APIEndpoint "" }}{{ printf "api-endpoint = %s\n" .Global.APIEndpoint }}{{ end }}{{ if ne .Global.ContainerAPIEndpoint "" }}{{ printf "container-api-endpoint = %s\n" .Global.ContainerAPIEndpoint }}{{ end }}{{ if ne .Global.TokenURL "" }}{{ printf "token-url = %s\n" .Global.TokenURL }}{{ end -}}
+{{ if ne .Global.StackType "" }}{{ printf "stack-type = %s\n" .Global.StackType }}{{ end -}}
 {{ if ne .Global.NetworkProjectID "" }}network-project-id = {{.Global.NetworkProjectID}}{{end}}
 
 `
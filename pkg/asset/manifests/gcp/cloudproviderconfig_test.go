@@ -4,6 +4,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/openshift/installer/pkg/ipnet"
+	"github.com/openshift/installer/pkg/types"
+	gcptypes "github.com/openshift/installer/pkg/types/gcp"
 )
 
 func TestCloudProviderConfig(t *testing.T) {
@@ -20,7 +24,11 @@ subnetwork-name = uid-worker-subnet
 
 
 `
-	actualConfig, err := CloudProviderConfig("uid", "test-project-id", "uid-worker-subnet", "")
+	actualConfig, err := CloudProviderConfig{
+		InfraID:    "uid",
+		ProjectID:  "test-project-id",
+		SubnetName: "uid-worker-subnet",
+	}.INI()
 	assert.NoError(t, err, "failed to create cloud provider config")
 	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
 }
@@ -39,7 +47,240 @@ subnetwork-name = uid-worker-subnet
 network-project-id = test-network-project-id
 
 `
-	actualConfig, err := CloudProviderConfig("uid", "test-project-id", "uid-worker-subnet", "test-network-project-id")
+	actualConfig, err := CloudProviderConfig{
+		InfraID:          "uid",
+		ProjectID:        "test-project-id",
+		SubnetName:       "uid-worker-subnet",
+		NetworkProjectID: "test-network-project-id",
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigWithServiceEndpoints(t *testing.T) {
+	expectedConfig := `[global]
+project-id      = test-project-id
+regional        = true
+multizone       = true
+node-tags       = uid-master
+node-tags       = uid-control-plane
+node-tags       = uid-worker
+node-instance-prefix = uid
+external-instance-groups-prefix = uid
+subnetwork-name = uid-worker-subnet
+api-endpoint = https://compute.example.com/compute/v1/
+container-api-endpoint = https://container.example.com
+
+
+`
+	serviceEndpoints := []gcptypes.ServiceEndpoint{
+		{Name: gcptypes.ComputeServiceEndpoint, URL: "https://compute.example.com/compute/v1/"},
+		{Name: gcptypes.StorageServiceEndpoint, URL: "https://storage.example.com"},
+		{Name: gcptypes.ContainerServiceEndpoint, URL: "https://container.example.com"},
+	}
+	actualConfig, err := CloudProviderConfig{
+		InfraID:          "uid",
+		ProjectID:        "test-project-id",
+		SubnetName:       "uid-worker-subnet",
+		ServiceEndpoints: serviceEndpoints,
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
+}
+
+func TestCloudProviderConfigCredentialsMode(t *testing.T) {
+	cases := []struct {
+		name            string
+		credentialsMode types.CredentialsMode
+		expectTokenURL  bool
+	}{{
+		name: "default",
+	}, {
+		name:            "mint",
+		credentialsMode: types.MintCredentialsMode,
+	}, {
+		name:            "passthrough",
+		credentialsMode: types.PassthroughCredentialsMode,
+	}, {
+		name:            "manual",
+		credentialsMode: types.ManualCredentialsMode,
+		expectTokenURL:  true,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actualConfig, err := CloudProviderConfig{
+				InfraID:          "uid",
+				ProjectID:        "test-project-id",
+				SubnetName:       "uid-worker-subnet",
+				NetworkProjectID: "test-network-project-id",
+				CredentialsMode:  tc.credentialsMode,
+			}.INI()
+			assert.NoError(t, err, "failed to create cloud provider config")
+			if tc.expectTokenURL {
+				assert.Contains(t, actualConfig, "subnetwork-name = uid-worker-subnet\ntoken-url = nil\nnetwork-project-id = test-network-project-id\n")
+			} else {
+				assert.NotContains(t, actualConfig, "token-url")
+			}
+		})
+	}
+}
+
+func TestCloudProviderConfigSingleZone(t *testing.T) {
+	expectedConfig := `[global]
+project-id      = test-project-id
+regional        = false
+multizone       = false
+local-zone      = us-central1-a
+node-tags       = uid-master
+node-tags       = uid-control-plane
+node-tags       = uid-worker
+node-instance-prefix = uid
+external-instance-groups-prefix = uid
+subnetwork-name = uid-worker-subnet
+
+
+`
+	actualConfig, err := CloudProviderConfig{
+		InfraID:    "uid",
+		ProjectID:  "test-project-id",
+		SubnetName: "uid-worker-subnet",
+		Zone:       "us-central1-a",
+	}.INI()
 	assert.NoError(t, err, "failed to create cloud provider config")
 	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
 }
+
+func TestCloudProviderConfigDualStack(t *testing.T) {
+	actualConfig, err := CloudProviderConfig{
+		InfraID:    "uid",
+		ProjectID:  "test-project-id",
+		SubnetName: "uid-worker-subnet",
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.NotContains(t, actualConfig, "stack-type")
+
+	actualConfig, err = CloudProviderConfig{
+		InfraID:    "uid",
+		ProjectID:  "test-project-id",
+		SubnetName: "uid-worker-subnet",
+		DualStack:  true,
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, actualConfig, "subnetwork-name = uid-worker-subnet\nstack-type = IPV4_IPV6\n")
+}
+
+func TestCloudProviderConfigNodeInstancePrefix(t *testing.T) {
+	actualConfig, err := CloudProviderConfig{
+		InfraID:    "uid",
+		ProjectID:  "test-project-id",
+		SubnetName: "uid-worker-subnet",
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, actualConfig, "node-instance-prefix = uid\n")
+
+	actualConfig, err = CloudProviderConfig{
+		InfraID:            "uid",
+		ProjectID:          "test-project-id",
+		SubnetName:         "uid-worker-subnet",
+		NodeInstancePrefix: "custom-nodes",
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, actualConfig, "node-instance-prefix = custom-nodes\n")
+	assert.Contains(t, actualConfig, "external-instance-groups-prefix = uid\n")
+}
+
+func TestCloudProviderConfigNetwork(t *testing.T) {
+	actualConfig, err := CloudProviderConfig{
+		InfraID:    "uid",
+		ProjectID:  "test-project-id",
+		SubnetName: "uid-worker-subnet",
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.NotContains(t, actualConfig, "\nnetwork-name")
+
+	actualConfig, err = CloudProviderConfig{
+		InfraID:     "uid",
+		ProjectID:   "test-project-id",
+		SubnetName:  "byo-subnet",
+		NetworkName: "byo-network",
+	}.INI()
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Contains(t, actualConfig, "subnetwork-name = byo-subnet\nnetwork-name = byo-network\n")
+}
+
+func TestIsDualStack(t *testing.T) {
+	cases := []struct {
+		name       string
+		networking *types.Networking
+		expected   bool
+	}{{
+		name: "no networking",
+	}, {
+		name: "IPv4",
+		networking: &types.Networking{
+			MachineNetwork: []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")}},
+			ClusterNetwork: []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.128.0.0/14"), HostPrefix: 23}},
+			ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16")},
+		},
+	}, {
+		name: "dual-stack",
+		networking: &types.Networking{
+			MachineNetwork: []types.MachineNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.0.0.0/16")}, {CIDR: *ipnet.MustParseCIDR("fd00::/48")}},
+			ClusterNetwork: []types.ClusterNetworkEntry{{CIDR: *ipnet.MustParseCIDR("10.128.0.0/14"), HostPrefix: 23}, {CIDR: *ipnet.MustParseCIDR("fd01::/48"), HostPrefix: 64}},
+			ServiceNetwork: []ipnet.IPNet{*ipnet.MustParseCIDR("172.30.0.0/16"), *ipnet.MustParseCIDR("fd02::/112")},
+		},
+		expected: true,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsDualStack(tc.networking))
+		})
+	}
+}
+
+func TestSingleZone(t *testing.T) {
+	cases := []struct {
+		name         string
+		defaultZones []string
+		masterZones  []string
+		workerZones  []string
+		expected     string
+	}{{
+		name: "no zones",
+	}, {
+		name:         "default zone",
+		defaultZones: []string{"us-central1-a"},
+		expected:     "us-central1-a",
+	}, {
+		name:        "same zone for all pools",
+		masterZones: []string{"us-central1-a"},
+		workerZones: []string{"us-central1-a"},
+		expected:    "us-central1-a",
+	}, {
+		name:        "multiple zones",
+		masterZones: []string{"us-central1-a", "us-central1-b"},
+		workerZones: []string{"us-central1-a"},
+	}, {
+		name:        "pools in different zones",
+		masterZones: []string{"us-central1-a"},
+		workerZones: []string{"us-central1-b"},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ic := &types.InstallConfig{
+				ControlPlane: &types.MachinePool{
+					Platform: types.MachinePoolPlatform{GCP: &gcptypes.MachinePool{Zones: tc.masterZones}},
+				},
+				Compute: []types.MachinePool{{
+					Platform: types.MachinePoolPlatform{GCP: &gcptypes.MachinePool{Zones: tc.workerZones}},
+				}},
+				Platform: types.Platform{
+					GCP: &gcptypes.Platform{
+						DefaultMachinePlatform: &gcptypes.MachinePool{Zones: tc.defaultZones},
+					},
+				},
+			}
+			assert.Equal(t, tc.expected, SingleZone(ic))
+		})
+	}
+}
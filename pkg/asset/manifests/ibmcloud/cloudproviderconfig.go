@@ -2,6 +2,7 @@ package ibmcloud
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"regexp"
 	"sort"
@@ -11,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	configv1 "github.com/openshift/api/config/v1"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
 )
 
 // https://github.com/kubernetes/kubernetes/blob/368ee4bb8ee7a0c18431cd87ee49f0c890aa53e5/staging/src/k8s.io/legacy-cloud-providers/gce/gce.go#L188
@@ -41,10 +43,18 @@ type provider struct {
 	IAMEndpointOverride      string `gcfg:"iamEndpointOverride,omitempty"`
 	VPCEndpointOverride      string `gcfg:"g2EndpointOverride,omitempty"`
 	RMEndpointOverride       string `gcfg:"rmEndpointOverride,omitempty"`
+	G2LoadBalancerProfile    string `gcfg:"g2LoadBalancerProfile,omitempty"`
+	SatelliteLocation        string `gcfg:"satelliteLocation,omitempty"`
 }
 
 // CloudProviderConfig generates the cloud provider config for the IBMCloud platform.
-func CloudProviderConfig(infraID string, accountID string, region string, resourceGroupName string, vpcName string, subnets []string, controlPlaneZones []string, computeZones []string, serviceEndpoints []configv1.IBMCloudServiceEndpoint) (string, error) {
+// The satellite configuration is nil for installs which do not target an IBM Cloud
+// Satellite location.
+func CloudProviderConfig(infraID string, accountID string, region string, resourceGroupName string, vpcName string, subnets []string, controlPlaneZones []string, computeZones []string, serviceEndpoints []configv1.IBMCloudServiceEndpoint, loadBalancerProfile ibmcloudtypes.LoadBalancerProfile, satellite *ibmcloudtypes.Satellite) (string, error) {
+	if satellite != nil && satellite.Location == "" {
+		return "", errors.New("the Satellite location must be set for IBM Cloud Satellite installs")
+	}
+
 	if vpcName == "" {
 		vpcName = fmt.Sprintf("%s-vpc", infraID)
 	}
@@ -73,8 +83,12 @@ func CloudProviderConfig(infraID string, accountID string, region string, resour
 			G2VPCName:                vpcName,
 			G2WorkerServiceAccountID: accountID,
 			G2VPCSubnetNames:         subnetNames,
+			G2LoadBalancerProfile:    string(loadBalancerProfile),
 		},
 	}
+	if satellite != nil {
+		config.Provider.SatelliteLocation = satellite.Location
+	}
 
 	// Add any IBM Cloud Service Endpoint overrides as necessary
 	for _, endpoint := range serviceEndpoints {
@@ -130,6 +144,6 @@ g2ResourceGroupName = {{.Provider.G2ResourceGroupName}}
 g2VpcName = {{.Provider.G2VPCName}}
 g2workerServiceAccountID = {{.Provider.G2WorkerServiceAccountID}}
 g2VpcSubnetNames = {{.Provider.G2VPCSubnetNames}}
-{{ if ne .Provider.IAMEndpointOverride ""}}{{ printf "iamEndpointOverride = %s\n" .Provider.IAMEndpointOverride }}{{ end }}{{ if ne .Provider.VPCEndpointOverride ""}}{{ printf "g2EndpointOverride = %s\n" .Provider.VPCEndpointOverride }}{{ end }}{{ if ne .Provider.RMEndpointOverride ""}}{{ printf "rmEndpointOverride = %s\n" .Provider.RMEndpointOverride }}{{ end }}
+{{ if ne .Provider.IAMEndpointOverride ""}}{{ printf "iamEndpointOverride = %s\n" .Provider.IAMEndpointOverride }}{{ end }}{{ if ne .Provider.VPCEndpointOverride ""}}{{ printf "g2EndpointOverride = %s\n" .Provider.VPCEndpointOverride }}{{ end }}{{ if ne .Provider.RMEndpointOverride ""}}{{ printf "rmEndpointOverride = %s\n" .Provider.RMEndpointOverride }}{{ end }}{{ if ne .Provider.G2LoadBalancerProfile ""}}{{ printf "g2LoadBalancerProfile = %s\n" .Provider.G2LoadBalancerProfile }}{{ end }}{{ if ne .Provider.SatelliteLocation ""}}{{ printf "satelliteLocation = %s\n" .Provider.SatelliteLocation }}{{ end }}
 
 `
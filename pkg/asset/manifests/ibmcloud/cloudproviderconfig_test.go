@@ -1,11 +1,13 @@
 package ibmcloud
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	configv1 "github.com/openshift/api/config/v1"
+	ibmcloudtypes "github.com/openshift/installer/pkg/types/ibmcloud"
 )
 
 func TestCloudProviderConfig(t *testing.T) {
@@ -108,6 +110,8 @@ rmEndpointOverride = https://ibmcloud.resource-manager.override.endpoint.test
 		cpZones           []string
 		computeZones      []string
 		serviceEndpoints  []configv1.IBMCloudServiceEndpoint
+		lbProfile         ibmcloudtypes.LoadBalancerProfile
+		satellite         *ibmcloudtypes.Satellite
 		expectedConfig    string
 	}{
 		{
@@ -122,6 +126,45 @@ rmEndpointOverride = https://ibmcloud.resource-manager.override.endpoint.test
 			computeZones:      useastZones,
 			expectedConfig:    defaultConfig,
 		},
+		{
+			name:              "application load balancer profile config",
+			infraID:           "ocp4-8pxks",
+			accountID:         accountID,
+			region:            "us-east",
+			resourceGroupName: "ocp4-8pxks-rg",
+			vpcName:           "ocp4-8pxks-vpc",
+			subnets:           []string{},
+			cpZones:           useastZones,
+			computeZones:      useastZones,
+			lbProfile:         ibmcloudtypes.ApplicationLoadBalancerProfile,
+			expectedConfig:    strings.Replace(defaultConfig, "\n\n\n", "\ng2LoadBalancerProfile = application\n\n\n", 1),
+		},
+		{
+			name:              "network load balancer profile config",
+			infraID:           "ocp4-8pxks",
+			accountID:         accountID,
+			region:            "us-east",
+			resourceGroupName: "ocp4-8pxks-rg",
+			vpcName:           "ocp4-8pxks-vpc",
+			subnets:           []string{},
+			cpZones:           useastZones,
+			computeZones:      useastZones,
+			lbProfile:         ibmcloudtypes.NetworkLoadBalancerProfile,
+			expectedConfig:    strings.Replace(defaultConfig, "\n\n\n", "\ng2LoadBalancerProfile = network\n\n\n", 1),
+		},
+		{
+			name:              "satellite location config",
+			infraID:           "ocp4-8pxks",
+			accountID:         accountID,
+			region:            "us-east",
+			resourceGroupName: "ocp4-8pxks-rg",
+			vpcName:           "ocp4-8pxks-vpc",
+			subnets:           []string{},
+			cpZones:           useastZones,
+			computeZones:      useastZones,
+			satellite:         &ibmcloudtypes.Satellite{Location: "c2b5gk6w0c5fd0p0s8ag"},
+			expectedConfig:    strings.Replace(defaultConfig, "\n\n\n", "\nsatelliteLocation = c2b5gk6w0c5fd0p0s8ag\n\n\n", 1),
+		},
 		{
 			name:              "existing subnet config",
 			infraID:           "ocp4-hf4vtt",
@@ -186,9 +229,14 @@ rmEndpointOverride = https://ibmcloud.resource-manager.override.endpoint.test
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			actualConfig, err := CloudProviderConfig(tc.infraID, tc.accountID, tc.region, tc.resourceGroupName, tc.vpcName, tc.subnets, tc.cpZones, tc.computeZones, tc.serviceEndpoints)
+			actualConfig, err := CloudProviderConfig(tc.infraID, tc.accountID, tc.region, tc.resourceGroupName, tc.vpcName, tc.subnets, tc.cpZones, tc.computeZones, tc.serviceEndpoints, tc.lbProfile, tc.satellite)
 			assert.NoError(t, err, "failed to create cloud provider config")
 			assert.Equal(t, tc.expectedConfig, actualConfig, "unexpected cloud provider config")
 		})
 	}
 }
+
+func TestCloudProviderConfigSatelliteWithoutLocation(t *testing.T) {
+	_, err := CloudProviderConfig("ocp4-8pxks", "1e1f75646aef447814a6d907cc83fb3c", "us-east", "ocp4-8pxks-rg", "ocp4-8pxks-vpc", nil, []string{"us-east-1"}, []string{"us-east-1"}, nil, "", &ibmcloudtypes.Satellite{})
+	assert.EqualError(t, err, "the Satellite location must be set for IBM Cloud Satellite installs")
+}
@@ -64,7 +64,7 @@ func (*Infrastructure) Dependencies() []asset.Asset {
 //
 //nolint:gocyclo
 func (i *Infrastructure) Generate(ctx context.Context, dependencies asset.Parents) error {
-	cloudProviderConfigMapKey := cloudProviderConfigDataKey
+	cloudProviderConfigMapKey := ConfigDataKey
 	clusterID := &installconfig.ClusterID{}
 	installConfig := &installconfig.InstallConfig{}
 	cloudproviderconfig := &CloudProviderConfig{}
@@ -332,9 +332,16 @@ func (i *Infrastructure) Generate(ctx context.Context, dependencies asset.Parent
 	config.Status.Platform = config.Spec.PlatformSpec.Type
 	config.Status.PlatformStatus.Type = config.Spec.PlatformSpec.Type
 
-	if cloudproviderconfig.ConfigMap != nil {
-		// set the configmap reference.
-		config.Spec.CloudConfig = configv1.ConfigMapFileReference{Name: cloudproviderconfig.ConfigMap.Name, Key: cloudProviderConfigMapKey}
+	if cm := cloudproviderconfig.ConfigMap; cm != nil {
+		// The ConfigMap has no config on the platforms without a cloud provider
+		// config once external cloud providers are previewed, or only the
+		// provider of an external platform. It is still written, but not
+		// referenced then, since components expect the referenced key to hold
+		// a config, see https://bugzilla.redhat.com/show_bug.cgi?id=1926975.
+		if cm.Data[cloudProviderConfigMapKey] != "" {
+			// set the configmap reference.
+			config.Spec.CloudConfig = configv1.ConfigMapFileReference{Name: cm.Name, Key: cloudProviderConfigMapKey}
+		}
 		i.FileList = append(i.FileList, cloudproviderconfig.File)
 	}
 
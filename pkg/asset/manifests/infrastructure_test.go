@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/yaml"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/openshift/installer/pkg/types"
 	awstypes "github.com/openshift/installer/pkg/types/aws"
 	azuretypes "github.com/openshift/installer/pkg/types/azure"
+	externaltypes "github.com/openshift/installer/pkg/types/external"
 	gcptypes "github.com/openshift/installer/pkg/types/gcp"
 	nonetypes "github.com/openshift/installer/pkg/types/none"
 )
@@ -107,6 +109,67 @@ func TestGenerateInfrastructure(t *testing.T) {
 	}
 }
 
+func TestGenerateInfrastructureEmptyCloudProviderConfig(t *testing.T) {
+	cases := []struct {
+		name          string
+		installConfig *types.InstallConfig
+		expectedData  map[string]string
+	}{{
+		name: "preview feature set without a cloud provider config",
+		installConfig: icBuild.build(icBuild.forNone(), func(ic *types.InstallConfig) {
+			ic.FeatureSet = configv1.TechPreviewNoUpgrade
+		}),
+	}, {
+		name: "external provider without a config",
+		installConfig: icBuild.build(func(ic *types.InstallConfig) {
+			ic.Platform.External = &externaltypes.Platform{
+				PlatformName:      "test-platform",
+				CloudProviderName: "test-provider",
+			}
+		}),
+		expectedData: map[string]string{ProviderDataKey: "test-provider"},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloudProviderConfig, parents := newTestCloudProviderConfig(tc.installConfig, nil)
+			if !assert.NoError(t, cloudProviderConfig.Generate(context.Background(), parents), "failed to generate cloud provider config") {
+				return
+			}
+			if !assert.NotNil(t, cloudProviderConfig.ConfigMap, "expected a cloud provider config map") {
+				return
+			}
+			assert.NotContains(t, cloudProviderConfig.ConfigMap.Data, ConfigDataKey)
+
+			parents.Add(cloudProviderConfig, &AdditionalTrustBundleConfig{})
+			infraAsset := &Infrastructure{}
+			if !assert.NoError(t, infraAsset.Generate(context.Background(), parents), "failed to generate asset") {
+				return
+			}
+			// The ConfigMap is written along with the Infrastructure manifest,
+			// but not referenced since it holds no config.
+			if !assert.Len(t, infraAsset.FileList, 2, "did not generate expected amount of files") {
+				return
+			}
+			assert.Equal(t, cloudProviderConfig.File, infraAsset.FileList[0])
+			var cm corev1.ConfigMap
+			if !assert.NoError(t, yaml.Unmarshal(infraAsset.FileList[0].Data, &cm), "failed to unmarshal cloud provider config manifest") {
+				return
+			}
+			assert.Equal(t, "cloud-provider-config", cm.Name)
+			if len(tc.expectedData) == 0 {
+				assert.Empty(t, cm.Data)
+			} else {
+				assert.Equal(t, tc.expectedData, cm.Data)
+			}
+			var actualInfra configv1.Infrastructure
+			if !assert.NoError(t, yaml.Unmarshal(infraAsset.FileList[1].Data, &actualInfra), "failed to unmarshal infra manifest") {
+				return
+			}
+			assert.Empty(t, actualInfra.Spec.CloudConfig)
+		})
+	}
+}
+
 type icOption func(*types.InstallConfig)
 
 type icBuildNamespace struct{}
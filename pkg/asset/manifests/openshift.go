@@ -124,7 +124,7 @@ func (o *Openshift) Generate(ctx context.Context, dependencies asset.Parents) er
 				Base64encodeClientID:       base64.StdEncoding.EncodeToString([]byte(creds.ClientID)),
 				Base64encodeClientSecret:   base64.StdEncoding.EncodeToString([]byte(creds.ClientSecret)),
 				Base64encodeTenantID:       base64.StdEncoding.EncodeToString([]byte(creds.TenantID)),
-				Base64encodeResourcePrefix: base64.StdEncoding.EncodeToString([]byte(clusterID.InfraID)),
+				Base64encodeResourcePrefix: base64.StdEncoding.EncodeToString([]byte(installConfig.Config.Azure.CloudProviderResourcePrefix(clusterID.InfraID))),
 				Base64encodeResourceGroup:  base64.StdEncoding.EncodeToString([]byte(resourceGroupName)),
 				Base64encodeRegion:         base64.StdEncoding.EncodeToString([]byte(installConfig.Config.Azure.Region)),
 			},
@@ -160,7 +160,7 @@ func (o *Openshift) Generate(ctx context.Context, dependencies asset.Parents) er
 
 		// We need to replace the local cacert path with one that is used in OpenShift
 		if cloud.CACertFile != "" {
-			cloud.CACertFile = "/etc/kubernetes/static-pod-resources/configmaps/cloud-config/ca-bundle.pem"
+			cloud.CACertFile = cloudProviderConfigCABundlePath
 		}
 
 		// Application credentials are easily rotated in the event of a leak and should be preferred. Encourage their use.
@@ -1,17 +1,25 @@
 package openstack
 
 import (
+	"bytes"
 	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/utils/v2/openstack/clientconfig"
 	networkutils "github.com/gophercloud/utils/v2/openstack/networking/v2/networks"
+	"github.com/sirupsen/logrus"
 
 	"github.com/openshift/installer/pkg/asset/installconfig/openstack"
 	"github.com/openshift/installer/pkg/types"
+	openstacktypes "github.com/openshift/installer/pkg/types/openstack"
 	openstackdefaults "github.com/openshift/installer/pkg/types/openstack/defaults"
 )
 
@@ -55,6 +63,17 @@ func CloudProviderConfigSecret(cloud *clientconfig.Cloud) ([]byte, error) {
 	if cloud.AuthInfo.Password != "" {
 		res.WriteString("password = " + strconv.Quote(cloud.AuthInfo.Password) + "\n")
 	}
+	// The cloud provider authenticates with the application credential when
+	// one is set, there is no separate auth type setting.
+	if cloud.AuthInfo.ApplicationCredentialID != "" {
+		res.WriteString("application-credential-id = " + strconv.Quote(cloud.AuthInfo.ApplicationCredentialID) + "\n")
+	}
+	if cloud.AuthInfo.ApplicationCredentialName != "" {
+		res.WriteString("application-credential-name = " + strconv.Quote(cloud.AuthInfo.ApplicationCredentialName) + "\n")
+	}
+	if cloud.AuthInfo.ApplicationCredentialSecret != "" {
+		res.WriteString("application-credential-secret = " + strconv.Quote(cloud.AuthInfo.ApplicationCredentialSecret) + "\n")
+	}
 	if cloud.AuthInfo.ProjectID != "" {
 		res.WriteString("tenant-id = " + strconv.Quote(cloud.AuthInfo.ProjectID) + "\n")
 	}
@@ -77,17 +96,42 @@ func CloudProviderConfigSecret(cloud *clientconfig.Cloud) ([]byte, error) {
 	return []byte(res.String()), nil
 }
 
+// networkIDFromName returns the ID of the network with the name.
+var networkIDFromName = networkutils.IDFromName
+
 func generateCloudProviderConfig(ctx context.Context, networkClient *gophercloud.ServiceClient, cloudConfig *clientconfig.Cloud, installConfig types.InstallConfig) (cloudProviderConfigData, cloudProviderConfigCABundleData string, err error) {
 	cloudProviderConfigData = `[Global]
 secret-name = openstack-credentials
 secret-namespace = kube-system
 `
-	if regionName := cloudConfig.RegionName; regionName != "" {
+	regionName, err := cloudRegionName(cloudConfig, installConfig.OpenStack.RegionName)
+	if err != nil {
+		return "", "", Error{err, "failed to select the region of the cloud"}
+	}
+	if regionName != "" {
 		cloudProviderConfigData += "region = " + regionName + "\n"
 	}
 
+	// The nodes of the additional regions are only managed when all the
+	// regions are listed, including the one of the clouds.yaml cloud.
+	var additionalRegions []openstacktypes.AdditionalRegion
+	for _, region := range installConfig.OpenStack.AdditionalRegions {
+		if region.Name != regionName {
+			additionalRegions = append(additionalRegions, region)
+		}
+	}
+	if len(additionalRegions) > 0 {
+		if regionName == "" {
+			return "", "", Error{errors.New("the clouds.yaml cloud has no region"), "failed to configure the additional regions"}
+		}
+		cloudProviderConfigData += "regions = " + regionName + "\n"
+		for _, region := range additionalRegions {
+			cloudProviderConfigData += "regions = " + region.Name + "\n"
+		}
+	}
+
+	hasCAFile := cloudConfig.CACertFile != ""
 	if caCertFile := cloudConfig.CACertFile; caCertFile != "" {
-		cloudProviderConfigData += "ca-file = /etc/kubernetes/static-pod-resources/configmaps/cloud-config/ca-bundle.pem\n"
 		caFile, err := os.ReadFile(caCertFile)
 		if err != nil {
 			return "", "", Error{err, "failed to read clouds.yaml ca-cert from disk"}
@@ -95,32 +139,217 @@ secret-namespace = kube-system
 		cloudProviderConfigCABundleData = string(caFile)
 	}
 
+	// Octavia can be fronted by another CA than Keystone, so its CA is added
+	// to the bundle since the cloud provider reads a single CA file.
+	if caCertFile := installConfig.OpenStack.LoadBalancerCACertFile; caCertFile != "" {
+		loadBalancerCAFile, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return "", "", Error{err, "failed to read the load balancer ca-cert from disk"}
+		}
+		if err := validateCACertificates(loadBalancerCAFile); err != nil {
+			return "", "", Error{err, "invalid load balancer ca-cert " + caCertFile}
+		}
+		var appended bool
+		cloudProviderConfigCABundleData, appended = appendCABundle(cloudProviderConfigCABundleData, string(loadBalancerCAFile))
+		hasCAFile = hasCAFile || appended
+	}
+
+	// The regions can share the CA of the cloud or have their own.
+	for _, region := range additionalRegions {
+		if region.CACertFile == "" {
+			continue
+		}
+		regionCAFile, err := os.ReadFile(region.CACertFile)
+		if err != nil {
+			return "", "", Error{err, "failed to read the ca-cert of region " + region.Name + " from disk"}
+		}
+		var appended bool
+		cloudProviderConfigCABundleData, appended = appendCABundle(cloudProviderConfigCABundleData, string(regionCAFile))
+		hasCAFile = hasCAFile || appended
+	}
+
+	if hasCAFile {
+		cloudProviderConfigData += "ca-file = /etc/kubernetes/static-pod-resources/configmaps/cloud-config/ca-bundle.pem\n"
+	}
+
+	// The certificates are verified unless the clouds.yaml cloud disables it
+	// explicitly, e.g. for self-signed endpoints without a CA file.
+	if verify := cloudConfig.Verify; verify != nil && !*verify {
+		logrus.Warn("The clouds.yaml cloud sets verify to false, the cloud provider will NOT verify the TLS certificates of the OpenStack endpoints")
+		cloudProviderConfigData += "tls-insecure = true\n"
+	}
+
+	// The networks are looked up once, since the load balancer classes can
+	// share the external network.
+	networkIDs := map[string]string{}
+	lookupNetworkID := func(networkName string) (string, error) {
+		if id, ok := networkIDs[networkName]; ok {
+			return id, nil
+		}
+		id, err := networkIDFromName(ctx, networkClient, networkName)
+		if err != nil {
+			return "", err
+		}
+		networkIDs[networkName] = id
+		return id, nil
+	}
+
+	var loadBalancerConfig string
 	if installConfig.OpenStack.ExternalNetwork != "" {
 		networkName := installConfig.OpenStack.ExternalNetwork // Yes, we use a name in install-config.yaml :/
-		networkID, err := networkutils.IDFromName(ctx, networkClient, networkName)
+		networkID, err := lookupNetworkID(networkName)
 		if err != nil {
 			return "", "", Error{err, "failed to fetch external network " + networkName}
 		}
 		// If set get the ID and configure CCM to use that network for LB FIPs.
-		cloudProviderConfigData += "\n[LoadBalancer]\n"
+		loadBalancerConfig += "floating-network-id = " + networkID + "\n"
+	}
+	if installConfig.OpenStack.ManageSecurityGroups {
+		loadBalancerConfig += "manage-security-groups = true\n"
+	}
+	if loadBalancerConfig != "" {
+		cloudProviderConfigData += "\n[LoadBalancer]\n" + loadBalancerConfig
+	}
+
+	// The classes without a floating network allocate their floating IPs from
+	// the one of the [LoadBalancer] section, if any.
+	for _, class := range installConfig.OpenStack.LoadBalancerClasses {
+		cloudProviderConfigData += "\n[LoadBalancerClass " + strconv.Quote(class.Name) + "]\n"
+		if class.FloatingNetwork == "" {
+			continue
+		}
+		networkID, err := lookupNetworkID(class.FloatingNetwork)
+		if err != nil {
+			return "", "", Error{err, "failed to fetch floating network " + class.FloatingNetwork + " of load balancer class " + class.Name}
+		}
 		cloudProviderConfigData += "floating-network-id = " + networkID + "\n"
 	}
 
+	// The cloud provider keeps its default search order when none is set.
+	if searchOrder := installConfig.OpenStack.MetadataSearchOrder; len(searchOrder) > 0 {
+		sources := make([]string, 0, len(searchOrder))
+		for _, source := range searchOrder {
+			sources = append(sources, string(source))
+		}
+		cloudProviderConfigData += "\n[Metadata]\nsearch-order = " + strings.Join(sources, ",") + "\n"
+	}
+
+	if internalNetworkName := installConfig.OpenStack.InternalNetworkName; internalNetworkName != "" {
+		cloudProviderConfigData += "\n[Networking]\ninternal-network-name = " + internalNetworkName + "\n"
+	}
+
 	return cloudProviderConfigData, cloudProviderConfigCABundleData, nil
 }
 
+// appendCABundle adds the CA to the bundle, unless the bundle already holds it.
+// It returns whether the CA was added.
+func appendCABundle(bundle, ca string) (string, bool) {
+	if strings.TrimSpace(ca) == "" || strings.Contains(bundle, strings.TrimSpace(ca)) {
+		return bundle, false
+	}
+	if bundle != "" && !strings.HasSuffix(bundle, "\n") {
+		bundle += "\n"
+	}
+	return bundle + ca, true
+}
+
+// validateCACertificates checks that the data holds PEM certificates only.
+func validateCACertificates(data []byte) error {
+	rest := data
+	blocks := 0
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks++
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("block %d is a %s, not a CERTIFICATE", blocks, block.Type)
+		}
+		if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+			return fmt.Errorf("block %d: %w", blocks, err)
+		}
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return errors.New("trailing data is not PEM encoded")
+	}
+	if blocks == 0 {
+		return errors.New("no PEM blocks found")
+	}
+	return nil
+}
+
+// cloudRegionName returns the region of the cloud the cluster is created in,
+// which is the given one, if any, or the region_name of the cloud. Clouds
+// listing several regions must select one of them, since the cloud provider
+// would otherwise look the resources of the cluster up in the wrong region.
+func cloudRegionName(cloudConfig *clientconfig.Cloud, regionName string) (string, error) {
+	names := make([]string, 0, len(cloudConfig.Regions))
+	for _, region := range cloudConfig.Regions {
+		names = append(names, region.Name)
+	}
+
+	if regionName == "" {
+		regionName = cloudConfig.RegionName
+		if regionName == "" && len(names) > 1 {
+			return "", fmt.Errorf("the clouds.yaml cloud has the regions %s but no region_name, set platform.openstack.regionName to one of them", strings.Join(names, ", "))
+		}
+		return regionName, nil
+	}
+	if len(names) > 0 && !slices.Contains(names, regionName) {
+		return "", fmt.Errorf("the region %q is not one of the regions %s of the clouds.yaml cloud", regionName, strings.Join(names, ", "))
+	}
+	return regionName, nil
+}
+
 // GenerateCloudProviderConfig adds the cloud provider config for the OpenStack
 // platform in the provided configmap.
 func GenerateCloudProviderConfig(ctx context.Context, installConfig types.InstallConfig) (cloudProviderConfigData, cloudProviderConfigCABundleData string, err error) {
-	session, err := openstack.GetSession(installConfig.Platform.OpenStack.Cloud)
+	cloudName, err := openstack.SelectCloud(installConfig.Platform.OpenStack.Cloud)
 	if err != nil {
 		return "", "", Error{err, "failed to get cloud config for openstack"}
 	}
+	session, err := openstack.GetSession(cloudName)
+	if err != nil {
+		return "", "", Error{err, "failed to get cloud config for openstack"}
+	}
+	return generateCloudProviderConfigWithSession(ctx, session, installConfig)
+}
+
+// GenerateCloudProviderConfigFromCloudsYAML adds the cloud provider config for
+// the OpenStack platform like GenerateCloudProviderConfig, with the cloud read
+// from the clouds.yaml content instead of the clouds.yaml files on disk.
+func GenerateCloudProviderConfigFromCloudsYAML(ctx context.Context, installConfig types.InstallConfig, cloudsYAML []byte) (cloudProviderConfigData, cloudProviderConfigCABundleData string, err error) {
+	cloudName, err := openstack.SelectCloudFromCloudsYAML(installConfig.Platform.OpenStack.Cloud, cloudsYAML)
+	if err != nil {
+		return "", "", Error{err, "failed to get cloud config for openstack from clouds.yaml"}
+	}
+	session, err := openstack.GetSessionFromCloudsYAML(cloudName, cloudsYAML)
+	if err != nil {
+		return "", "", Error{err, "failed to get cloud config for openstack from clouds.yaml"}
+	}
+	return generateCloudProviderConfigWithSession(ctx, session, installConfig)
+}
+
+func generateCloudProviderConfigWithSession(ctx context.Context, session *openstack.Session, installConfig types.InstallConfig) (cloudProviderConfigData, cloudProviderConfigCABundleData string, err error) {
+	clientOpts, cloudConfig := session.ClientOpts, session.CloudConfig
+	// The cloud is loaded again in the selected region, so that the values of
+	// the region override the ones of the cloud and the clients are created in
+	// the region.
+	if regionName := installConfig.OpenStack.RegionName; regionName != "" {
+		regionOpts := *clientOpts
+		regionOpts.RegionName = regionName
+		clientOpts = &regionOpts
+		if cloudConfig, err = clientconfig.GetCloudFromYAML(clientOpts); err != nil {
+			return "", "", Error{err, "failed to get cloud config for openstack region " + regionName}
+		}
+	}
 
-	networkClient, err := openstackdefaults.NewServiceClient(ctx, "network", session.ClientOpts)
+	networkClient, err := openstackdefaults.NewServiceClient(ctx, "network", clientOpts)
 	if err != nil {
 		return "", "", Error{err, "failed to create a network client"}
 	}
 
-	return generateCloudProviderConfig(ctx, networkClient, session.CloudConfig, installConfig)
+	return generateCloudProviderConfig(ctx, networkClient, cloudConfig, installConfig)
 }
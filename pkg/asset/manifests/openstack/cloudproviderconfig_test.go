@@ -2,9 +2,22 @@ package openstack
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gophercloud/gophercloud/v2"
 	"github.com/gophercloud/utils/v2/openstack/clientconfig"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/openshift/installer/pkg/types"
@@ -65,6 +78,28 @@ region = "my_region"
 	assert.Equal(t, expectedConfig, string(actualConfig), "unexpected cloud provider config")
 }
 
+func TestCloudProviderConfigSecretApplicationCredential(t *testing.T) {
+	cloud := clientconfig.Cloud{
+		AuthType: clientconfig.AuthV3ApplicationCredential,
+		AuthInfo: &clientconfig.AuthInfo{
+			AuthURL:                     "https://my_auth_url.com/v3/",
+			ApplicationCredentialID:     "8a6f9b0a1f1a4c2e9f3d7c5b4a3e2d1c",
+			ApplicationCredentialSecret: "my_secret",
+		},
+		RegionName: "my_region",
+	}
+
+	expectedConfig := `[Global]
+auth-url = "https://my_auth_url.com/v3/"
+application-credential-id = "8a6f9b0a1f1a4c2e9f3d7c5b4a3e2d1c"
+application-credential-secret = "my_secret"
+region = "my_region"
+`
+	actualConfig, err := CloudProviderConfigSecret(&cloud)
+	assert.NoError(t, err, "failed to create cloud provider config")
+	assert.Equal(t, expectedConfig, string(actualConfig), "unexpected cloud provider config")
+}
+
 func TestCloudProviderConfigSecretQuoting(t *testing.T) {
 	passwords := map[string]string{
 		"regular":        "regular",
@@ -112,6 +147,97 @@ func TestCloudProviderConfig(t *testing.T) {
 secret-name = openstack-credentials
 secret-namespace = kube-system
 region = my_region
+`,
+		},
+		{
+			name: "manage security groups",
+			installConfig: &types.InstallConfig{
+				Networking: &types.Networking{},
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						ManageSecurityGroups: true,
+					},
+				},
+			},
+			expectedConfig: `[Global]
+secret-name = openstack-credentials
+secret-namespace = kube-system
+region = my_region
+
+[LoadBalancer]
+manage-security-groups = true
+`,
+		},
+		{
+			name: "additional regions",
+			installConfig: &types.InstallConfig{
+				Networking: &types.Networking{},
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						AdditionalRegions: []openstack.AdditionalRegion{{Name: "my_region"}, {Name: "other_region"}},
+					},
+				},
+			},
+			expectedConfig: `[Global]
+secret-name = openstack-credentials
+secret-namespace = kube-system
+region = my_region
+regions = my_region
+regions = other_region
+`,
+		},
+		{
+			name: "metadata search order",
+			installConfig: &types.InstallConfig{
+				Networking: &types.Networking{},
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						MetadataSearchOrder: []openstack.MetadataSource{openstack.MetadataServiceMetadataSource, openstack.ConfigDriveMetadataSource},
+					},
+				},
+			},
+			expectedConfig: `[Global]
+secret-name = openstack-credentials
+secret-namespace = kube-system
+region = my_region
+
+[Metadata]
+search-order = metadataService,configDrive
+`,
+		},
+		{
+			name: "empty metadata search order",
+			installConfig: &types.InstallConfig{
+				Networking: &types.Networking{},
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						MetadataSearchOrder: []openstack.MetadataSource{},
+					},
+				},
+			},
+			expectedConfig: `[Global]
+secret-name = openstack-credentials
+secret-namespace = kube-system
+region = my_region
+`,
+		},
+		{
+			name: "internal network name",
+			installConfig: &types.InstallConfig{
+				Networking: &types.Networking{},
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						InternalNetworkName: "my_network",
+					},
+				},
+			},
+			expectedConfig: `[Global]
+secret-name = openstack-credentials
+secret-namespace = kube-system
+region = my_region
+
+[Networking]
+internal-network-name = my_network
 `,
 		},
 	}
@@ -136,3 +262,310 @@ region = my_region
 		})
 	}
 }
+
+func TestCloudProviderConfigLoadBalancerClasses(t *testing.T) {
+	lookups := map[string]int{}
+	networks := map[string]string{"external": "external-id", "public": "public-id"}
+	originalNetworkIDFromName := networkIDFromName
+	networkIDFromName = func(_ context.Context, _ *gophercloud.ServiceClient, name string) (string, error) {
+		lookups[name]++
+		id, ok := networks[name]
+		if !ok {
+			return "", fmt.Errorf("network %s not found", name)
+		}
+		return id, nil
+	}
+	t.Cleanup(func() { networkIDFromName = originalNetworkIDFromName })
+
+	cloud := clientconfig.Cloud{RegionName: "my_region"}
+	installConfig := types.InstallConfig{
+		Networking: &types.Networking{},
+		Platform: types.Platform{
+			OpenStack: &openstack.Platform{
+				ExternalNetwork: "external",
+				LoadBalancerClasses: []openstack.LoadBalancerClass{
+					{Name: "public", FloatingNetwork: "public"},
+					{Name: "external", FloatingNetwork: "external"},
+					{Name: "default"},
+				},
+			},
+		},
+	}
+	expectedConfig := `[Global]
+secret-name = openstack-credentials
+secret-namespace = kube-system
+region = my_region
+
+[LoadBalancer]
+floating-network-id = external-id
+
+[LoadBalancerClass "public"]
+floating-network-id = public-id
+
+[LoadBalancerClass "external"]
+floating-network-id = external-id
+
+[LoadBalancerClass "default"]
+`
+	actualConfig, _, err := generateCloudProviderConfig(context.Background(), nil, &cloud, installConfig)
+	assert.NoError(t, err, "unexpected error when generating cloud provider config")
+	assert.Equal(t, expectedConfig, actualConfig, "unexpected cloud provider config")
+	assert.Equal(t, map[string]int{"external": 1, "public": 1}, lookups)
+
+	installConfig.OpenStack.LoadBalancerClasses = []openstack.LoadBalancerClass{{Name: "missing", FloatingNetwork: "missing"}}
+	_, _, err = generateCloudProviderConfig(context.Background(), nil, &cloud, installConfig)
+	assert.EqualError(t, err, "failed to fetch floating network missing of load balancer class missing: network missing not found")
+}
+
+func TestCloudProviderConfigAdditionalRegionsCA(t *testing.T) {
+	dir := t.TempDir()
+	writeCA := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	cloudCA := writeCA("cloud-ca.pem", "cloud-ca\n")
+	regionCA := writeCA("region-ca.pem", "region-ca\n")
+
+	cases := []struct {
+		name            string
+		cloudCACertFile string
+		regions         []openstack.AdditionalRegion
+		expectedBundle  string
+	}{{
+		name:            "shared CA",
+		cloudCACertFile: cloudCA,
+		regions:         []openstack.AdditionalRegion{{Name: "region-b"}, {Name: "region-c", CACertFile: cloudCA}},
+		expectedBundle:  "cloud-ca\n",
+	}, {
+		name:            "distinct CAs",
+		cloudCACertFile: cloudCA,
+		regions:         []openstack.AdditionalRegion{{Name: "region-b", CACertFile: regionCA}, {Name: "region-c", CACertFile: regionCA}},
+		expectedBundle:  "cloud-ca\nregion-ca\n",
+	}, {
+		name:           "only region CA",
+		regions:        []openstack.AdditionalRegion{{Name: "region-b", CACertFile: regionCA}},
+		expectedBundle: "region-ca\n",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := clientconfig.Cloud{CACertFile: tc.cloudCACertFile, RegionName: "region-a"}
+			installConfig := types.InstallConfig{
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						AdditionalRegions: tc.regions,
+					},
+				},
+			}
+			config, bundle, err := generateCloudProviderConfig(context.Background(), nil, &cloud, installConfig)
+			if !assert.NoError(t, err, "unexpected error when generating cloud provider config") {
+				return
+			}
+			assert.Contains(t, config, "ca-file = /etc/kubernetes/static-pod-resources/configmaps/cloud-config/ca-bundle.pem\n")
+			assert.Equal(t, tc.expectedBundle, bundle)
+		})
+	}
+}
+
+// testCACertificate returns a PEM-encoded self-signed CA certificate.
+func testCACertificate(t *testing.T) string {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "octavia-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestCloudProviderConfigLoadBalancerCA(t *testing.T) {
+	dir := t.TempDir()
+	writeCA := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	loadBalancerCA := testCACertificate(t)
+	cloudCA := writeCA("cloud-ca.pem", "cloud-ca\n")
+	octaviaCA := writeCA("octavia-ca.pem", loadBalancerCA)
+	corruptedCA := writeCA("corrupted-ca.pem", "corrupted\n")
+
+	cases := []struct {
+		name                   string
+		cloudCACertFile        string
+		loadBalancerCACertFile string
+		expectedBundle         string
+		expectedError          string
+	}{{
+		name:            "only cloud CA",
+		cloudCACertFile: cloudCA,
+		expectedBundle:  "cloud-ca\n",
+	}, {
+		name:                   "distinct CAs",
+		cloudCACertFile:        cloudCA,
+		loadBalancerCACertFile: octaviaCA,
+		expectedBundle:         "cloud-ca\n" + loadBalancerCA,
+	}, {
+		name:                   "only load balancer CA",
+		loadBalancerCACertFile: octaviaCA,
+		expectedBundle:         loadBalancerCA,
+	}, {
+		name:                   "corrupted load balancer CA",
+		cloudCACertFile:        cloudCA,
+		loadBalancerCACertFile: corruptedCA,
+		expectedError:          "invalid load balancer ca-cert " + corruptedCA + ": trailing data is not PEM encoded",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cloud := clientconfig.Cloud{CACertFile: tc.cloudCACertFile}
+			installConfig := types.InstallConfig{
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{
+						LoadBalancerCACertFile: tc.loadBalancerCACertFile,
+					},
+				},
+			}
+			config, bundle, err := generateCloudProviderConfig(context.Background(), nil, &cloud, installConfig)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if !assert.NoError(t, err, "unexpected error when generating cloud provider config") {
+				return
+			}
+			assert.Contains(t, config, "ca-file = /etc/kubernetes/static-pod-resources/configmaps/cloud-config/ca-bundle.pem\n")
+			assert.Equal(t, tc.expectedBundle, bundle)
+		})
+	}
+}
+
+func TestCloudProviderConfigAdditionalRegionsWithoutRegion(t *testing.T) {
+	installConfig := types.InstallConfig{
+		Platform: types.Platform{
+			OpenStack: &openstack.Platform{
+				AdditionalRegions: []openstack.AdditionalRegion{{Name: "region-b"}},
+			},
+		},
+	}
+	_, _, err := generateCloudProviderConfig(context.Background(), nil, &clientconfig.Cloud{}, installConfig)
+	assert.EqualError(t, err, "failed to configure the additional regions: the clouds.yaml cloud has no region")
+}
+
+func TestCloudProviderConfigRegion(t *testing.T) {
+	regions := []clientconfig.Region{{Name: "region-a"}, {Name: "region-b"}}
+	cases := []struct {
+		name           string
+		cloud          clientconfig.Cloud
+		regionName     string
+		expectedRegion string
+		expectedError  string
+	}{{
+		name:           "region of the cloud",
+		cloud:          clientconfig.Cloud{RegionName: "region-a"},
+		expectedRegion: "region-a",
+	}, {
+		name: "no region",
+	}, {
+		name:  "single region",
+		cloud: clientconfig.Cloud{Regions: regions[:1]},
+	}, {
+		name:           "selected region",
+		cloud:          clientconfig.Cloud{RegionName: "region-a", Regions: regions},
+		regionName:     "region-b",
+		expectedRegion: "region-b",
+	}, {
+		name:           "selected region of a cloud without regions",
+		regionName:     "region-b",
+		expectedRegion: "region-b",
+	}, {
+		name:          "several regions and none selected",
+		cloud:         clientconfig.Cloud{Regions: regions},
+		expectedError: "failed to select the region of the cloud: the clouds.yaml cloud has the regions region-a, region-b but no region_name, set platform.openstack.regionName to one of them",
+	}, {
+		name:          "unknown selected region",
+		cloud:         clientconfig.Cloud{Regions: regions},
+		regionName:    "region-c",
+		expectedError: `failed to select the region of the cloud: the region "region-c" is not one of the regions region-a, region-b of the clouds.yaml cloud`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			installConfig := types.InstallConfig{
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{RegionName: tc.regionName},
+				},
+			}
+			config, _, err := generateCloudProviderConfig(context.Background(), nil, &tc.cloud, installConfig)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			if tc.expectedRegion == "" {
+				assert.NotContains(t, config, "region = ")
+				return
+			}
+			assert.Contains(t, config, "\nregion = "+tc.expectedRegion+"\n")
+		})
+	}
+}
+
+func TestCloudProviderConfigTLSInsecure(t *testing.T) {
+	insecure, secure := false, true
+	cases := []struct {
+		name           string
+		verify         *bool
+		expectInsecure bool
+	}{{
+		name: "verify unset",
+	}, {
+		name:   "verify true",
+		verify: &secure,
+	}, {
+		name:           "verify false",
+		verify:         &insecure,
+		expectInsecure: true,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			hook := logrustest.NewGlobal()
+			defer hook.Reset()
+
+			cloud := clientconfig.Cloud{RegionName: "my_region", Verify: tc.verify}
+			installConfig := types.InstallConfig{
+				Platform: types.Platform{
+					OpenStack: &openstack.Platform{},
+				},
+			}
+			config, _, err := generateCloudProviderConfig(context.Background(), nil, &cloud, installConfig)
+			if !assert.NoError(t, err, "unexpected error when generating cloud provider config") {
+				return
+			}
+			if tc.expectInsecure {
+				assert.Contains(t, config, "region = my_region\ntls-insecure = true\n")
+				if assert.NotNil(t, hook.LastEntry()) {
+					assert.Equal(t, logrus.WarnLevel, hook.LastEntry().Level)
+				}
+			} else {
+				assert.NotContains(t, config, "tls-insecure")
+				assert.Nil(t, hook.LastEntry())
+			}
+		})
+	}
+}
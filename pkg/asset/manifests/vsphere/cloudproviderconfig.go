@@ -3,27 +3,106 @@ package vsphere
 import (
 	"bytes"
 	"fmt"
+	"path"
 	"strings"
 
+	"github.com/sirupsen/logrus"
 	yaml "gopkg.in/yaml.v2"
 	cloudconfig "k8s.io/cloud-provider-vsphere/pkg/common/config"
+	"k8s.io/utils/strings/slices"
 
 	vspheretypes "github.com/openshift/installer/pkg/types/vsphere"
 )
 
-const (
-	regionTagCategory = "openshift-region"
-	zoneTagCategory   = "openshift-zone"
-)
-
 func printIfNotEmpty(buf *bytes.Buffer, k, v string) {
 	if v != "" {
 		fmt.Fprintf(buf, "%s = %q\n", k, v)
 	}
 }
 
+// insecureVCenters returns whether certificate verification must be skipped for
+// at least one vCenter, which is the case when any of them has no thumbprint.
+func insecureVCenters(vCenters []vspheretypes.VCenter) bool {
+	for _, vCenter := range vCenters {
+		if vCenter.Thumbprint == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFolderPath checks that the folder of the VMs is an absolute inventory
+// path of the form /<datacenter>/vm/<folder>, since the cloud provider does not
+// find the VMs otherwise. The datacenter of a folder provided by the user must
+// be the datacenter of the failure domain.
+func validateFolderPath(folderPath, datacenter string, userProvided bool) error {
+	if !strings.HasPrefix(folderPath, "/") {
+		return fmt.Errorf("folder %s must be an absolute path of the form /<datacenter>/vm/<folder>", folderPath)
+	}
+	idx := strings.Index(folderPath, "/vm/")
+	if idx < 0 || strings.Trim(folderPath[idx+len("/vm/"):], "/") == "" {
+		return fmt.Errorf("folder %s must be a path of the form /<datacenter>/vm/<folder>", folderPath)
+	}
+	if userProvided {
+		folderDatacenter := path.Clean(folderPath[:idx])
+		if folderDatacenter != path.Clean("/"+datacenter) {
+			return fmt.Errorf("folder %s is not in datacenter %s", folderPath, datacenter)
+		}
+	}
+	return nil
+}
+
+// topologyTagCategories returns the tag categories of the regions and zones for
+// the Labels of the cloud provider config. They are empty for single-zone
+// installs, unless the tag categories are set explicitly.
+func topologyTagCategories(p *vspheretypes.Platform) (string, string) {
+	if len(p.FailureDomains) <= 1 && !p.HasTopologyTagCategories() {
+		return "", ""
+	}
+	return p.RegionTagCategoryName(), p.ZoneTagCategoryName()
+}
+
+// cloudProviderConfigYAML adds the node settings of the out of tree cloud
+// provider, which are missing from the vendored config types.
+type cloudProviderConfigYAML struct {
+	cloudconfig.CommonConfigYAML `yaml:",inline"`
+
+	Nodes *nodesYAML `yaml:"nodes,omitempty"`
+}
+
+// nodesYAML selects the network used for the addresses of the nodes.
+type nodesYAML struct {
+	InternalVMNetworkName string `yaml:"internalVmNetworkName,omitempty"`
+}
+
+// nodeNetworkName returns the network providing the addresses of the nodes. It
+// is empty when every failure domain has a single network, so that the cloud
+// provider keeps picking the only NIC of the nodes.
+func nodeNetworkName(p *vspheretypes.Platform, nodeNetwork string) (string, error) {
+	for _, failureDomain := range p.FailureDomains {
+		networks := failureDomain.Topology.Networks
+		if nodeNetwork == "" {
+			if len(networks) > 1 {
+				return "", fmt.Errorf("failure domain %s has multiple networks, the network providing the node addresses must be set", failureDomain.Name)
+			}
+			continue
+		}
+		if len(networks) > 0 && !slices.Contains(networks, nodeNetwork) {
+			return "", fmt.Errorf("node network %s is not a network of failure domain %s", nodeNetwork, failureDomain.Name)
+		}
+	}
+	return nodeNetwork, nil
+}
+
 // CloudProviderConfigYaml generates the yaml out of tree cloud provider config for the vSphere platform.
-func CloudProviderConfigYaml(infraID string, p *vspheretypes.Platform) (string, error) {
+// nodeNetwork is the network providing the node addresses, it must be set when
+// a failure domain has more than one network.
+func CloudProviderConfigYaml(infraID string, p *vspheretypes.Platform, nodeNetwork string) (string, error) {
+	nodeNetwork, err := nodeNetworkName(p, nodeNetwork)
+	if err != nil {
+		return "", err
+	}
+
 	vCenters := make(map[string]*cloudconfig.VirtualCenterConfigYAML)
 
 	for _, vCenter := range p.VCenters {
@@ -35,27 +114,34 @@ func CloudProviderConfigYaml(infraID string, p *vspheretypes.Platform) (string,
 			VCenterIP:    vCenter.Server,
 			VCenterPort:  uint(vCenterPort),
 			Datacenters:  vCenter.Datacenters,
-			InsecureFlag: true,
+			InsecureFlag: vCenter.Thumbprint == "",
+			Thumbprint:   vCenter.Thumbprint,
 		}
 		vCenters[vCenter.Server] = &vCenterConfig
 	}
 
-	cloudProviderConfig := cloudconfig.CommonConfigYAML{
-		Global: cloudconfig.GlobalYAML{
-			SecretName:      "vsphere-creds",
-			SecretNamespace: "kube-system",
-			InsecureFlag:    true,
+	cloudProviderConfig := cloudProviderConfigYAML{
+		CommonConfigYAML: cloudconfig.CommonConfigYAML{
+			Global: cloudconfig.GlobalYAML{
+				SecretName:      "vsphere-creds",
+				SecretNamespace: "kube-system",
+				InsecureFlag:    insecureVCenters(p.VCenters),
+			},
+			Vcenter: vCenters,
 		},
-		Vcenter: vCenters,
 	}
 
-	if len(p.FailureDomains) > 1 {
+	if region, zone := topologyTagCategories(p); region != "" {
 		cloudProviderConfig.Labels = cloudconfig.LabelsYAML{
-			Zone:   vspheretypes.TagCategoryZone,
-			Region: vspheretypes.TagCategoryRegion,
+			Zone:   zone,
+			Region: region,
 		}
 	}
 
+	if nodeNetwork != "" {
+		cloudProviderConfig.Nodes = &nodesYAML{InternalVMNetworkName: nodeNetwork}
+	}
+
 	cloudProviderConfigYaml, err := yaml.Marshal(cloudProviderConfig)
 	if err != nil {
 		return "", err
@@ -66,13 +152,26 @@ func CloudProviderConfigYaml(infraID string, p *vspheretypes.Platform) (string,
 // CloudProviderConfigIni generates the multi-zone ini cloud provider config
 // for the vSphere platform. folderPath is the absolute path to the VM folder that will be
 // used for installation. p is the vSphere platform struct.
-func CloudProviderConfigIni(infraID string, p *vspheretypes.Platform) (string, error) {
+// The first failure domain must have either a datastore or a datastore URL, or
+// else a datastore cluster.
+// The insecure-flag is only left out when every vCenter has a thumbprint, since
+// the in-tree provider applies it to all vCenters. nodeNetwork is the network
+// providing the node addresses, it must be set when a failure domain has more
+// than one network.
+func CloudProviderConfigIni(infraID string, p *vspheretypes.Platform, nodeNetwork string) (string, error) {
+	nodeNetwork, err := nodeNetworkName(p, nodeNetwork)
+	if err != nil {
+		return "", err
+	}
+
 	buf := new(bytes.Buffer)
 
 	fmt.Fprintln(buf, "[Global]")
 	printIfNotEmpty(buf, "secret-name", "vsphere-creds")
 	printIfNotEmpty(buf, "secret-namespace", "kube-system")
-	printIfNotEmpty(buf, "insecure-flag", "1")
+	if insecureVCenters(p.VCenters) {
+		printIfNotEmpty(buf, "insecure-flag", "1")
+	}
 	fmt.Fprintln(buf, "")
 
 	for _, vcenter := range p.VCenters {
@@ -99,26 +198,57 @@ func CloudProviderConfigIni(infraID string, p *vspheretypes.Platform) (string, e
 			}
 		}
 		printIfNotEmpty(buf, "datacenters", strings.Join(datacenters, ","))
+		printIfNotEmpty(buf, "thumbprint", vcenter.Thumbprint)
 	}
 	fmt.Fprintln(buf, "")
 
 	fmt.Fprintln(buf, "[Workspace]")
 	printIfNotEmpty(buf, "server", p.FailureDomains[0].Server)
 	printIfNotEmpty(buf, "datacenter", p.FailureDomains[0].Topology.Datacenter)
-	printIfNotEmpty(buf, "default-datastore", p.FailureDomains[0].Topology.Datastore)
+	// The in-tree provider takes a single storage target, so a datastore
+	// cluster is only used when no datastore is set.
+	topology := p.FailureDomains[0].Topology
+	if topology.Datastore != "" && topology.DatastoreURL != "" {
+		return "", fmt.Errorf("failure domain %s has both a datastore and a datastore URL", p.FailureDomains[0].Name)
+	}
+	switch {
+	case topology.DatastoreURL != "":
+		if topology.DatastoreCluster != "" {
+			logrus.Warnf("Both datastore URL %s and datastore cluster %s are set for failure domain %s, using the datastore URL in the cloud provider config", topology.DatastoreURL, topology.DatastoreCluster, p.FailureDomains[0].Name)
+		}
+		printIfNotEmpty(buf, "default-datastore-url", topology.DatastoreURL)
+	case topology.Datastore != "":
+		if topology.DatastoreCluster != "" {
+			logrus.Warnf("Both datastore %s and datastore cluster %s are set for failure domain %s, using the datastore in the cloud provider config", topology.Datastore, topology.DatastoreCluster, p.FailureDomains[0].Name)
+		}
+		printIfNotEmpty(buf, "default-datastore", topology.Datastore)
+	case topology.DatastoreCluster != "":
+		printIfNotEmpty(buf, "default-datastore-cluster", topology.DatastoreCluster)
+	default:
+		return "", fmt.Errorf("failure domain %s has neither a datastore nor a datastore cluster", p.FailureDomains[0].Name)
+	}
 
 	folderPath := fmt.Sprintf("/%s/vm/%s", p.FailureDomains[0].Topology.Datacenter, infraID)
 	if p.FailureDomains[0].Topology.Folder != "" {
 		folderPath = p.FailureDomains[0].Topology.Folder
 	}
+	if err := validateFolderPath(folderPath, p.FailureDomains[0].Topology.Datacenter, p.FailureDomains[0].Topology.Folder != ""); err != nil {
+		return "", err
+	}
 	printIfNotEmpty(buf, "folder", folderPath)
 	printIfNotEmpty(buf, "resourcepool-path", p.FailureDomains[0].Topology.ResourcePool)
 	fmt.Fprintln(buf, "")
 
-	if len(p.FailureDomains) > 1 {
+	if nodeNetwork != "" {
+		fmt.Fprintln(buf, "[Nodes]")
+		printIfNotEmpty(buf, "internal-vm-network-name", nodeNetwork)
+		fmt.Fprintln(buf, "")
+	}
+
+	if region, zone := topologyTagCategories(p); region != "" {
 		fmt.Fprintln(buf, "[Labels]")
-		printIfNotEmpty(buf, "region", regionTagCategory)
-		printIfNotEmpty(buf, "zone", zoneTagCategory)
+		printIfNotEmpty(buf, "region", region)
+		printIfNotEmpty(buf, "zone", zone)
 	}
 
 	return buf.String(), nil
@@ -9,6 +9,8 @@ import (
 	vsphere "github.com/openshift/installer/pkg/types/vsphere"
 )
 
+const testThumbprint = "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01"
+
 var (
 	expectedIniConfig = `[Global]
 secret-name = "vsphere-creds"
@@ -145,7 +147,7 @@ func TestCloudProviderConfig(t *testing.T) {
 		{
 			name:                "valid intree cloud provider config",
 			platform:            validPlatform(),
-			cloudProviderFunc:   CloudProviderConfigIni,
+			cloudProviderFunc:   iniWithNodeNetwork(""),
 			expectedCloudConfig: expectedIniConfig + expectIniLabelsSection,
 		},
 		{
@@ -158,17 +160,82 @@ func TestCloudProviderConfig(t *testing.T) {
 
 				return p
 			}(),
-			cloudProviderFunc: CloudProviderConfigIni,
+			cloudProviderFunc: iniWithNodeNetwork(""),
 			expectedCloudConfig: func() string {
 				// only a single datacenter would be provided to the datacenters
 				ini := strings.ReplaceAll(expectedIniConfig, ",test-datacenter2", "")
 				return ini
 			}(),
 		},
+		{
+			name: "intree cloud provider config with vCenter thumbprint",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.VCenters[0].Thumbprint = testThumbprint
+				return p
+			}(),
+			cloudProviderFunc: iniWithNodeNetwork(""),
+			expectedCloudConfig: func() string {
+				ini := strings.Replace(expectedIniConfig, "insecure-flag = \"1\"\n", "", 1)
+				ini = strings.Replace(ini, "test-datacenter2\"\n", "test-datacenter2\"\nthumbprint = \""+testThumbprint+"\"\n", 1)
+				return ini + expectIniLabelsSection
+			}(),
+		},
+		{
+			name: "intree cloud provider config with thumbprint for only one of multiple vCenters",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.VCenters[0].Thumbprint = testThumbprint
+				p.VCenters = append(p.VCenters, vsphere.VCenter{
+					Server:      "test-vcenter2",
+					Datacenters: []string{"test-datacenter3"},
+				})
+				return p
+			}(),
+			cloudProviderFunc: iniWithNodeNetwork(""),
+			expectedCloudConfig: func() string {
+				ini := strings.Replace(expectedIniConfig, "test-datacenter2\"\n", "test-datacenter2\"\nthumbprint = \""+testThumbprint+"\"\n[VirtualCenter \"test-vcenter2\"]\ndatacenters = \"test-datacenter3\"\n", 1)
+				return ini + expectIniLabelsSection
+			}(),
+		},
+		{
+			name: "intree cloud provider config with datastore cluster",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.Datastore = ""
+				p.FailureDomains[0].Topology.DatastoreCluster = "/test-datacenter/datastore/test-datastore-cluster"
+				return p
+			}(),
+			cloudProviderFunc:   iniWithNodeNetwork(""),
+			expectedCloudConfig: strings.Replace(expectedIniConfig, "default-datastore = \"test-datastore\"", "default-datastore-cluster = \"/test-datacenter/datastore/test-datastore-cluster\"", 1) + expectIniLabelsSection,
+		},
+		{
+			name: "intree cloud provider config with datastore and datastore cluster",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.DatastoreCluster = "/test-datacenter/datastore/test-datastore-cluster"
+				return p
+			}(),
+			cloudProviderFunc:   iniWithNodeNetwork(""),
+			expectedCloudConfig: expectedIniConfig + expectIniLabelsSection,
+		},
+		{
+			name: "out of tree yaml cloud provider config with vCenter thumbprint",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.VCenters[0].Thumbprint = testThumbprint
+				return p
+			}(),
+			cloudProviderFunc: yamlWithNodeNetwork(""),
+			expectedCloudConfig: func() string {
+				yaml := strings.ReplaceAll(expectedYamlConfig, "insecureFlag: true", "insecureFlag: false")
+				return strings.Replace(yaml, "    thumbprint: \"\"", "    thumbprint: "+testThumbprint, 1)
+			}(),
+		},
 		{
 			name:                "valid out of tree yaml cloud provider config",
 			platform:            validPlatform(),
-			cloudProviderFunc:   CloudProviderConfigYaml,
+			cloudProviderFunc:   yamlWithNodeNetwork(""),
 			expectedCloudConfig: expectedYamlConfig,
 		},
 	}
@@ -183,3 +250,187 @@ func TestCloudProviderConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestCloudProviderConfigIniWithoutStorage(t *testing.T) {
+	p := validPlatform()
+	p.FailureDomains[0].Topology.Datastore = ""
+	_, err := CloudProviderConfigIni("infraID", p, "")
+	assert.EqualError(t, err, "failure domain test-dz-east-1a has neither a datastore nor a datastore cluster")
+}
+
+func TestCloudProviderConfigIniDatastoreURL(t *testing.T) {
+	p := validPlatform()
+	p.FailureDomains[0].Topology.Datastore = ""
+	p.FailureDomains[0].Topology.DatastoreURL = "ds:///vmfs/volumes/vsan:52c6a2b1c3d4e5f6-0123456789abcdef/"
+	actual, err := CloudProviderConfigIni("infraID", p, "")
+	if assert.NoError(t, err) {
+		assert.Contains(t, actual, "datacenter = \"test-datacenter\"\ndefault-datastore-url = \"ds:///vmfs/volumes/vsan:52c6a2b1c3d4e5f6-0123456789abcdef/\"\nfolder = ")
+		assert.NotContains(t, actual, "default-datastore =")
+	}
+
+	p.FailureDomains[0].Topology.Datastore = "/test-datacenter/datastore/test-datastore"
+	_, err = CloudProviderConfigIni("infraID", p, "")
+	assert.EqualError(t, err, "failure domain test-dz-east-1a has both a datastore and a datastore URL")
+}
+
+func TestCloudProviderConfigIniFolder(t *testing.T) {
+	cases := []struct {
+		name          string
+		folder        string
+		expectedError string
+	}{{
+		name: "default folder",
+	}, {
+		name:   "user folder",
+		folder: "/test-datacenter/vm/test-folder/nested",
+	}, {
+		name:          "relative folder",
+		folder:        "test-datacenter/vm/test-folder",
+		expectedError: "folder test-datacenter/vm/test-folder must be an absolute path of the form /<datacenter>/vm/<folder>",
+	}, {
+		name:          "folder without vm segment",
+		folder:        "/test-datacenter/test-folder",
+		expectedError: "folder /test-datacenter/test-folder must be a path of the form /<datacenter>/vm/<folder>",
+	}, {
+		name:          "folder in another datacenter",
+		folder:        "/test-datacenter2/vm/test-folder",
+		expectedError: "folder /test-datacenter2/vm/test-folder is not in datacenter test-datacenter",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := validPlatform()
+			p.FailureDomains[0].Topology.Folder = tc.folder
+			_, err := CloudProviderConfigIni("infraID", p, "")
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func iniWithNodeNetwork(nodeNetwork string) func(string, *vsphere.Platform) (string, error) {
+	return func(infraID string, p *vsphere.Platform) (string, error) {
+		return CloudProviderConfigIni(infraID, p, nodeNetwork)
+	}
+}
+
+func yamlWithNodeNetwork(nodeNetwork string) func(string, *vsphere.Platform) (string, error) {
+	return func(infraID string, p *vsphere.Platform) (string, error) {
+		return CloudProviderConfigYaml(infraID, p, nodeNetwork)
+	}
+}
+
+func TestCloudProviderConfigNodeNetwork(t *testing.T) {
+	multipleNetworks := func() *vsphere.Platform {
+		p := validPlatform()
+		for i := range p.FailureDomains {
+			p.FailureDomains[i].Topology.Networks = append(p.FailureDomains[i].Topology.Networks, "test-network-2")
+		}
+		return p
+	}
+
+	cases := []struct {
+		name          string
+		platform      *vsphere.Platform
+		nodeNetwork   string
+		expectedIni   string
+		expectedYaml  string
+		expectedError string
+	}{{
+		name:         "single network",
+		platform:     validPlatform(),
+		expectedIni:  expectedIniConfig + expectIniLabelsSection,
+		expectedYaml: expectedYamlConfig,
+	}, {
+		name:         "multiple networks with a node network",
+		platform:     multipleNetworks(),
+		nodeNetwork:  "test-network-2",
+		expectedIni:  expectedIniConfig + "[Nodes]\ninternal-vm-network-name = \"test-network-2\"\n\n" + expectIniLabelsSection,
+		expectedYaml: expectedYamlConfig + "nodes:\n  internalVmNetworkName: test-network-2\n",
+	}, {
+		name:          "multiple networks without a node network",
+		platform:      multipleNetworks(),
+		expectedError: "failure domain test-dz-east-1a has multiple networks, the network providing the node addresses must be set",
+	}, {
+		name:          "node network of another failure domain",
+		platform:      validPlatform(),
+		nodeNetwork:   "test-network-2",
+		expectedError: "node network test-network-2 is not a network of failure domain test-dz-east-1a",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ini, err := CloudProviderConfigIni("infraID", tc.platform, tc.nodeNetwork)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedIni, ini)
+			}
+
+			yaml, err := CloudProviderConfigYaml("infraID", tc.platform, tc.nodeNetwork)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tc.expectedYaml, yaml)
+			}
+		})
+	}
+}
+
+func TestCloudProviderConfigTagCategories(t *testing.T) {
+	singleZone := func() *vsphere.Platform {
+		p := validPlatform()
+		p.FailureDomains = p.FailureDomains[:1]
+		return p
+	}
+	cases := []struct {
+		name           string
+		platform       *vsphere.Platform
+		expectedIni    string
+		expectedLabels string
+	}{{
+		name:     "single zone",
+		platform: singleZone(),
+	}, {
+		name: "single zone with tag categories",
+		platform: func() *vsphere.Platform {
+			p := singleZone()
+			p.RegionTagCategory = "k8s-region"
+			return p
+		}(),
+		expectedIni:    "[Labels]\nregion = \"k8s-region\"\nzone = \"openshift-zone\"\n",
+		expectedLabels: "labels:\n  zone: openshift-zone\n  region: k8s-region\n",
+	}, {
+		name: "multiple zones with tag categories",
+		platform: func() *vsphere.Platform {
+			p := validPlatform()
+			p.RegionTagCategory = "k8s-region"
+			p.ZoneTagCategory = "k8s-zone"
+			return p
+		}(),
+		expectedIni:    "[Labels]\nregion = \"k8s-region\"\nzone = \"k8s-zone\"\n",
+		expectedLabels: "labels:\n  zone: k8s-zone\n  region: k8s-region\n",
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ini, err := CloudProviderConfigIni("infraID", tc.platform, "")
+			if assert.NoError(t, err) {
+				if tc.expectedIni == "" {
+					assert.NotContains(t, ini, "[Labels]")
+				} else {
+					assert.True(t, strings.HasSuffix(ini, "\n\n"+tc.expectedIni), "unexpected labels in %s", ini)
+				}
+			}
+
+			yaml, err := CloudProviderConfigYaml("infraID", tc.platform, "")
+			if assert.NoError(t, err) {
+				if tc.expectedLabels == "" {
+					assert.Contains(t, yaml, "labels:\n  zone: \"\"\n  region: \"\"\n")
+				} else {
+					assert.Contains(t, yaml, tc.expectedLabels)
+				}
+			}
+		})
+	}
+}
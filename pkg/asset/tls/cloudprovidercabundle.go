@@ -35,7 +35,7 @@ func (a *CloudProviderCABundle) Generate(_ context.Context, deps asset.Parents)
 	if ic.Config.Platform.Name() != awstypes.Name {
 		return nil
 	}
-	if !awstypes.IsSecretRegion(ic.Config.Platform.AWS.Region) {
+	if !awstypes.IsIsolatedRegion(ic.Config.Platform.AWS.Region) {
 		return nil
 	}
 
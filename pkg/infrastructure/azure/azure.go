@@ -607,7 +607,7 @@ func (p *Provider) PostProvision(ctx context.Context, in clusterapi.PostProvisio
 		sshRuleName := fmt.Sprintf("%s_ssh_in", in.InfraID)
 		if err = addSecurityGroupRule(ctx, &securityGroupInput{
 			resourceGroupName:    p.ResourceGroupName,
-			securityGroupName:    fmt.Sprintf("%s-nsg", in.InfraID),
+			securityGroupName:    in.InstallConfig.Config.Azure.NetworkSecurityGroupName(in.InfraID),
 			securityRuleName:     sshRuleName,
 			securityRulePort:     "22",
 			securityRulePriority: 220,
@@ -684,7 +684,7 @@ func (p *Provider) PostDestroy(ctx context.Context, in clusterapi.PostDestroyerI
 	}
 
 	resourceGroupName := fmt.Sprintf("%s-rg", in.Metadata.InfraID)
-	securityGroupName := fmt.Sprintf("%s-nsg", in.Metadata.InfraID)
+	securityGroupName := (&aztypes.Platform{ResourcePrefix: in.Metadata.Azure.ResourcePrefix}).NetworkSecurityGroupName(in.Metadata.InfraID)
 	sshRuleName := fmt.Sprintf("%s_ssh_in", in.Metadata.InfraID)
 
 	// See if a security group rule exists with the name ${InfraID}_ssh_in.
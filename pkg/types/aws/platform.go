@@ -51,6 +51,13 @@ type Platform struct {
 	// +optional
 	HostedZoneRole string `json:"hostedZoneRole,omitempty"`
 
+	// CloudProviderRoleARN is the ARN of an IAM role to be assumed by the
+	// cloud provider, e.g. when the cluster resources belong to another account.
+	// When unset, the cloud provider uses the credentials of the cluster.
+	//
+	// +optional
+	CloudProviderRoleARN string `json:"cloudProviderRoleARN,omitempty"`
+
 	// UserTags additional keys and values that the installer will add
 	// as tags to all resources that it creates. Resources created by the
 	// cluster itself may not include these tags.
@@ -146,3 +153,25 @@ func IsSecretRegion(region string) bool {
 	}
 	return false
 }
+
+// IsChinaRegion returns true if the region is part of the China partition,
+// whose endpoints are under amazonaws.com.cn instead of amazonaws.com.
+func IsChinaRegion(region string) bool {
+	partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	return ok && partition.ID() == endpoints.AwsCnPartitionID
+}
+
+// IsIsolatedRegion returns true if the region is part of any of the isolated
+// partitions (ISO, ISOB, ISOE or ISOF), which use privately-signed endpoints and
+// therefore need the additional trust bundle to be reachable.
+func IsIsolatedRegion(region string) bool {
+	partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
+	if !ok {
+		return false
+	}
+	switch partition.ID() {
+	case endpoints.AwsIsoPartitionID, endpoints.AwsIsoBPartitionID, endpoints.AwsIsoEPartitionID, endpoints.AwsIsoFPartitionID:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsIsolatedRegion(t *testing.T) {
+	cases := []struct {
+		region   string
+		expected bool
+	}{
+		{region: "us-east-1"},
+		{region: "us-gov-west-1"},
+		{region: "cn-north-1"},
+		{region: "us-iso-east-1", expected: true},
+		{region: "us-isob-east-1", expected: true},
+		{region: "eu-isoe-west-1", expected: true},
+		{region: "us-isof-south-1", expected: true},
+		{region: "not-a-region"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.region, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsIsolatedRegion(tc.region))
+		})
+	}
+}
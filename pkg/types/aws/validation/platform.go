@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -51,6 +52,12 @@ func ValidatePlatform(p *aws.Platform, cm types.CredentialsMode, fldPath *field.
 		}
 	}
 
+	if p.CloudProviderRoleARN != "" {
+		if roleARN, err := arn.Parse(p.CloudProviderRoleARN); err != nil || roleARN.Service != "iam" || !strings.HasPrefix(roleARN.Resource, "role/") {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("cloudProviderRoleARN"), p.CloudProviderRoleARN, "must be the ARN of an IAM role"))
+		}
+	}
+
 	allErrs = append(allErrs, validateServiceEndpoints(p.ServiceEndpoints, fldPath.Child("serviceEndpoints"))...)
 	allErrs = append(allErrs, validateUserTags(p.UserTags, p.PropagateUserTag, fldPath.Child("userTags"))...)
 
@@ -195,6 +195,21 @@ func TestValidatePlatform(t *testing.T) {
 				HostedZoneRole: "test-hosted-zone-role",
 			},
 		},
+		{
+			name: "valid cloud provider role",
+			platform: &aws.Platform{
+				Region:               "us-east-1",
+				CloudProviderRoleARN: "arn:aws:iam::123456789012:role/cloud-provider",
+			},
+		},
+		{
+			name: "invalid cloud provider role",
+			platform: &aws.Platform{
+				Region:               "us-east-1",
+				CloudProviderRoleARN: "arn:aws:iam::123456789012:user/cloud-provider",
+			},
+			expected: `^test-path\.cloudProviderRoleARN: Invalid value: "arn:aws:iam::123456789012:user/cloud-provider": must be the ARN of an IAM role$`,
+		},
 		{
 			name: "hosted zone role without credential mode should error",
 			platform: &aws.Platform{
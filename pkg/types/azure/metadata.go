@@ -7,6 +7,7 @@ type Metadata struct {
 	Region                      string           `json:"region"`
 	ResourceGroupName           string           `json:"resourceGroupName"`
 	BaseDomainResourceGroupName string           `json:"baseDomainResourceGroupName"`
+	ResourcePrefix              string           `json:"resourcePrefix,omitempty"`
 }
 
 // Keys used to save Metadata information as tags.
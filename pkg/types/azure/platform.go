@@ -26,6 +26,19 @@ const (
 	UserDefinedRoutingOutboundType OutboundType = "UserDefinedRouting"
 )
 
+// LoadBalancerSKU is the SKU of the load balancers created by the cloud provider for Services.
+// +kubebuilder:validation:Enum="";standard;basic
+type LoadBalancerSKU string
+
+const (
+	// StandardLoadBalancerSKU uses Standard load balancers, which support TCP resets on idle.
+	// see https://docs.microsoft.com/en-us/azure/load-balancer/skus
+	StandardLoadBalancerSKU LoadBalancerSKU = "standard"
+
+	// BasicLoadBalancerSKU uses Basic load balancers, the only SKU available on Azure Stack.
+	BasicLoadBalancerSKU LoadBalancerSKU = "basic"
+)
+
 // Platform stores all the global configuration that all machinesets
 // use.
 type Platform struct {
@@ -69,6 +82,14 @@ type Platform struct {
 	// +optional
 	ComputeSubnet string `json:"computeSubnet,omitempty"`
 
+	// RouteTableName specifies the name of an existing route table of the virtual
+	// network for the routes of the nodes, which the cloud provider manages. When
+	// empty, the route table is named after the resource prefix of the cluster.
+	// Only allowed when a virtual network is specified.
+	//
+	// +optional
+	RouteTableName string `json:"routeTableName,omitempty"`
+
 	// cloudName is the name of the Azure cloud environment which can be used to configure the Azure SDK
 	// with the appropriate Azure API endpoints.
 	// If empty, the value is equal to "AzurePublicCloud".
@@ -100,6 +121,82 @@ type Platform struct {
 
 	// CustomerManagedKey has the keys needed to encrypt the storage account.
 	CustomerManagedKey *CustomerManagedKey `json:"customerManagedKey,omitempty"`
+
+	// PutVMSSVMBatchSize is the number of requests the cloud provider sends concurrently
+	// when updating the VMs of a virtual machine scale set.
+	// When unset, the requests are sent one by one.
+	//
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PutVMSSVMBatchSize int `json:"putVMSSVMBatchSize,omitempty"`
+
+	// LoadBalancerResourceGroupName is the name of an already existing resource group where the cloud
+	// provider creates the load balancers for Services, for example when the load balancers are managed
+	// separately from the cluster resources.
+	// If empty, the load balancers are created in the resource group of the cluster.
+	//
+	// +optional
+	LoadBalancerResourceGroupName string `json:"loadBalancerResourceGroupName,omitempty"`
+
+	// LoadBalancerSKU is the SKU of the load balancers created by the cloud provider for Services.
+	// If empty, Standard load balancers are used, or Basic ones when installing on Azure Stack.
+	//
+	// +optional
+	LoadBalancerSKU LoadBalancerSKU `json:"loadBalancerSKU,omitempty"`
+
+	// LoadBalancers are the additional standard load balancers, each with its own frontend IP
+	// configurations and backend pool, which the cloud provider can place the Services on besides
+	// the load balancer of the cluster, named after its infrastructure ID. The internal Services are
+	// placed on the <name>-internal load balancer of each of them, which is what the Services of
+	// clusters with the Internal publishing strategy use.
+	// They are only supported with standard load balancers.
+	//
+	// +optional
+	LoadBalancers []LoadBalancer `json:"loadBalancers,omitempty"`
+
+	// ResourcePrefix is the prefix of the names of the resources of the cluster shared with the
+	// cloud provider, such as the network security group and the route table, for example to
+	// match resources created beforehand with a different prefix. The installer names the
+	// network security group it creates with the same prefix.
+	// If empty, the infrastructure ID of the cluster is used.
+	//
+	// +optional
+	ResourcePrefix string `json:"resourcePrefix,omitempty"`
+
+	// ServiceEndpoints list contains custom endpoints which override the default
+	// public endpoints of the Azure services called by the cloud provider, for
+	// example the private link endpoints of clusters with restricted egress.
+	// They are not supported on Azure Stack Hub, whose endpoints are set with armEndpoint.
+	// There must be only one ServiceEndpoint for a service.
+	// +optional
+	ServiceEndpoints []ServiceEndpoint `json:"serviceEndpoints,omitempty"`
+}
+
+// ServiceEndpointName is the name of an Azure service whose endpoint can be overridden.
+type ServiceEndpointName string
+
+const (
+	// ResourceManagerServiceEndpoint is the endpoint override for the Azure Resource Manager API.
+	ResourceManagerServiceEndpoint ServiceEndpointName = "ResourceManager"
+
+	// ActiveDirectoryServiceEndpoint is the endpoint override for the Microsoft Entra ID authentication.
+	ActiveDirectoryServiceEndpoint ServiceEndpointName = "ActiveDirectory"
+)
+
+// ServiceEndpoint stores the configuration for services to
+// override existing defaults of Azure services.
+type ServiceEndpoint struct {
+	// Name is the name of the Azure service.
+	// This must be provided and cannot be empty.
+	// +kubebuilder:validation:Enum="ResourceManager";"ActiveDirectory"
+	Name ServiceEndpointName `json:"name"`
+
+	// URL is fully qualified URI with scheme https, that overrides the default generated
+	// endpoint for a client.
+	// This must be provided and cannot be empty.
+	//
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url"`
 }
 
 // KeyVault defines an Azure Key Vault.
@@ -163,6 +260,15 @@ func (p *Platform) ClusterResourceGroupName(infraID string) string {
 	return fmt.Sprintf("%s-rg", infraID)
 }
 
+// CloudProviderResourcePrefix returns the prefix of the names of the resources
+// of the cluster used by the cloud provider.
+func (p *Platform) CloudProviderResourcePrefix(infraID string) string {
+	if len(p.ResourcePrefix) > 0 {
+		return p.ResourcePrefix
+	}
+	return infraID
+}
+
 // VirtualNetworkName returns the name of the virtual network for the cluster.
 func (p *Platform) VirtualNetworkName(infraID string) string {
 	if len(p.VirtualNetwork) > 0 {
@@ -171,6 +277,35 @@ func (p *Platform) VirtualNetworkName(infraID string) string {
 	return fmt.Sprintf("%s-vnet", infraID)
 }
 
+// LoadBalancer is an additional standard load balancer of the cloud provider.
+type LoadBalancer struct {
+	// Name is the name of the load balancer.
+	Name string `json:"name"`
+
+	// PrimaryVMSet is the name of the availability set or scale set whose nodes are always in
+	// the backend pool of the load balancer. A set can only be primary for one load balancer.
+	//
+	// +optional
+	PrimaryVMSet string `json:"primaryVMSet,omitempty"`
+
+	// ServiceLabels are the labels the Services placed on the load balancer must have.
+	// If empty, Services with any labels can be placed on it.
+	//
+	// +optional
+	ServiceLabels map[string]string `json:"serviceLabels,omitempty"`
+
+	// ServiceNamespaceLabels are the labels the namespaces of the Services placed on the load
+	// balancer must have. If empty, Services of any namespace can be placed on it.
+	//
+	// +optional
+	ServiceNamespaceLabels map[string]string `json:"serviceNamespaceLabels,omitempty"`
+
+	// NodeLabels are the labels of the nodes preferably added to the backend pool of the load balancer.
+	//
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+}
+
 // ControlPlaneSubnetName returns the name of the control plane subnet for the
 // cluster.
 func (p *Platform) ControlPlaneSubnetName(infraID string) string {
@@ -190,7 +325,7 @@ func (p *Platform) ComputeSubnetName(infraID string) string {
 
 // NetworkSecurityGroupName returns the name of the network security group.
 func (p *Platform) NetworkSecurityGroupName(infraID string) string {
-	return fmt.Sprintf("%s-nsg", infraID)
+	return fmt.Sprintf("%s-nsg", p.CloudProviderResourcePrefix(infraID))
 }
 
 // IsARO returns true if ARO-only modifications are enabled
@@ -12,3 +12,12 @@ func TestSetBaseDomain(t *testing.T) {
 	platform.SetBaseDomain(zoneID)
 	assert.Equal(t, "<rg_name>", platform.BaseDomainResourceGroupName)
 }
+
+func TestNetworkSecurityGroupName(t *testing.T) {
+	platform := Platform{}
+	assert.Equal(t, "test-infra-id-nsg", platform.NetworkSecurityGroupName("test-infra-id"))
+
+	platform.ResourcePrefix = "byo-infra"
+	assert.Equal(t, "byo-infra-nsg", platform.NetworkSecurityGroupName("test-infra-id"))
+	assert.Equal(t, "byo-infra", platform.CloudProviderResourcePrefix("test-infra-id"))
+}
@@ -1,7 +1,9 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
 	"regexp"
 	"sort"
 	"strings"
@@ -49,6 +51,18 @@ var (
 
 	// keyVaultUserAssignedIdentityRegex is for verifying the user assigned identity key used for storage account encryption.
 	keyVaultUserAssignedIdentityRegex = regexp.MustCompile(`^[0-9A-Za-z][0-9A-Za-z-_]{2,127}$`)
+
+	// resourcePrefixRegex is for verifying that the names prefixed by the resource prefix are valid
+	// Azure resource names, which start with an alphanumeric and end with an alphanumeric or underscore.
+	resourcePrefixRegex = regexp.MustCompile(`^[0-9A-Za-z]([0-9A-Za-z_.-]{0,61}[0-9A-Za-z_])?$`)
+
+	// loadBalancerNameRegex is for verifying that the names of the additional load balancers are valid
+	// Azure load balancer names.
+	loadBalancerNameRegex = regexp.MustCompile(`^[0-9A-Za-z]([0-9A-Za-z_.-]{0,78}[0-9A-Za-z_])?$`)
+
+	// routeTableNameRegex is for verifying that the name of the existing route table is a valid
+	// Azure route table name.
+	routeTableNameRegex = regexp.MustCompile(`^[0-9A-Za-z]([0-9A-Za-z_.-]{0,78}[0-9A-Za-z_])?$`)
 )
 
 // maxUserTagLimit is the maximum userTags that can be configured as defined in openshift/api.
@@ -88,6 +102,20 @@ func ValidatePlatform(p *azure.Platform, publish types.PublishingStrategy, fldPa
 			allErrs = append(allErrs, field.Required(fldPath.Child("networkResourceGroupName"), "must provide a network resource group when supplying subnets"))
 		}
 	}
+	if p.RouteTableName != "" {
+		if p.VirtualNetwork == "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("routeTableName"), "a route table name is only allowed when a virtual network is specified"))
+		}
+		if !routeTableNameRegex.MatchString(p.RouteTableName) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("routeTableName"), p.RouteTableName, "must be at most 80 characters long, can only contain alphanumerics, underscores, periods and hyphens, and must start with an alphanumeric and end with an alphanumeric or underscore"))
+		}
+	}
+	if p.PutVMSSVMBatchSize < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("putVMSSVMBatchSize"), p.PutVMSSVMBatchSize, "must be a positive integer"))
+	}
+	if p.ResourcePrefix != "" && !resourcePrefixRegex.MatchString(p.ResourcePrefix) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("resourcePrefix"), p.ResourcePrefix, "must be at most 63 characters long, can only contain alphanumerics, underscores, periods and hyphens, and must start with an alphanumeric and end with an alphanumeric or underscore"))
+	}
 	if !validCloudNames[p.CloudName] {
 		allErrs = append(allErrs, field.NotSupported(fldPath.Child("cloudName"), p.CloudName, validCloudNameValues))
 	}
@@ -108,6 +136,14 @@ func ValidatePlatform(p *azure.Platform, publish types.PublishingStrategy, fldPa
 		}
 	}
 
+	if p.LoadBalancerSKU != "" {
+		if _, ok := validLoadBalancerSKUs[p.LoadBalancerSKU]; !ok {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("loadBalancerSKU"), p.LoadBalancerSKU, validLoadBalancerSKUValues))
+		}
+	}
+
+	allErrs = append(allErrs, validateLoadBalancers(p, fldPath)...)
+
 	if p.CustomerManagedKey != nil {
 		allErrs = append(allErrs, validateCustomerManagedKeys(p.CloudName, *p.CustomerManagedKey, fldPath.Child("customerManagedKey"))...)
 	}
@@ -120,9 +156,14 @@ func ValidatePlatform(p *azure.Platform, publish types.PublishingStrategy, fldPa
 	// check if configured userTags are valid.
 	allErrs = append(allErrs, validateUserTags(p.UserTags, fldPath.Child("userTags"))...)
 
+	allErrs = append(allErrs, validateServiceEndpoints(p.ServiceEndpoints, fldPath.Child("serviceEndpoints"))...)
+
 	switch cloud := p.CloudName; cloud {
 	case azure.StackCloud:
 		allErrs = append(allErrs, validateAzureStack(p, fldPath)...)
+		if len(p.ServiceEndpoints) > 0 {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("serviceEndpoints"), fmt.Sprintf("service endpoints are not supported on %s, the endpoints are set from armEndpoint", cloud)))
+		}
 	default:
 		if p.ARMEndpoint != "" {
 			allErrs = append(allErrs, field.Required(fldPath.Child("armEndpoint"), fmt.Sprintf("ARM endpoint must not be set when the cloud name is %s", cloud)))
@@ -135,6 +176,38 @@ func ValidatePlatform(p *azure.Platform, publish types.PublishingStrategy, fldPa
 	return allErrs
 }
 
+// validateServiceEndpoints checks that every service endpoint override names a known
+// service, is not duplicated, and is an https URL with a host.
+func validateServiceEndpoints(endpoints []azure.ServiceEndpoint, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	validNames := []string{string(azure.ResourceManagerServiceEndpoint), string(azure.ActiveDirectoryServiceEndpoint)}
+	tracker := map[azure.ServiceEndpointName]int{}
+	for idx, e := range endpoints {
+		fldp := fldPath.Index(idx)
+		switch e.Name {
+		case azure.ResourceManagerServiceEndpoint, azure.ActiveDirectoryServiceEndpoint:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldp.Child("name"), e.Name, validNames))
+		}
+		if eidx, ok := tracker[e.Name]; ok {
+			allErrs = append(allErrs, field.Invalid(fldp.Child("name"), e.Name, fmt.Sprintf("duplicate service endpoint not allowed for %s, service endpoint already defined at %s", e.Name, fldPath.Index(eidx))))
+		} else {
+			tracker[e.Name] = idx
+		}
+
+		u, err := url.Parse(e.URL)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldp.Child("url"), e.URL, err.Error()))
+		case u.Scheme != "https":
+			allErrs = append(allErrs, field.Invalid(fldp.Child("url"), e.URL, "only https scheme is allowed"))
+		case u.Hostname() == "":
+			allErrs = append(allErrs, field.Invalid(fldp.Child("url"), e.URL, "host cannot be empty"))
+		}
+	}
+	return allErrs
+}
+
 // validateCustomerManagedKeys validates the key vault id.
 func validateCustomerManagedKeys(cloudName azure.CloudEnvironment, s azure.CustomerManagedKey, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
@@ -255,12 +328,46 @@ var (
 		sort.Strings(v)
 		return v
 	}()
+
+	validLoadBalancerSKUs = map[azure.LoadBalancerSKU]struct{}{
+		azure.StandardLoadBalancerSKU: {},
+		azure.BasicLoadBalancerSKU:    {},
+	}
+
+	validLoadBalancerSKUValues = func() []string {
+		v := make([]string, 0, len(validLoadBalancerSKUs))
+		for m := range validLoadBalancerSKUs {
+			v = append(v, string(m))
+		}
+		sort.Strings(v)
+		return v
+	}()
 )
 
+// ValidateARMEndpoint checks that the ARM endpoint of Azure Stack Hub is an
+// https URL with a host, which the cloud provider can reach.
+func ValidateARMEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return errors.New("ARM endpoint must be set")
+	}
+	u, err := url.Parse(endpoint)
+	switch {
+	case err != nil:
+		return err
+	case u.Scheme != "https":
+		return errors.New("only https scheme is allowed")
+	case u.Hostname() == "":
+		return errors.New("host cannot be empty")
+	}
+	return nil
+}
+
 func validateAzureStack(p *azure.Platform, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 	if p.ARMEndpoint == "" {
 		allErrs = append(allErrs, field.Required(fldPath.Child("armEndpoint"), "ARM endpoint must be set when installing on Azure Stack"))
+	} else if err := ValidateARMEndpoint(p.ARMEndpoint); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("armEndpoint"), p.ARMEndpoint, err.Error()))
 	}
 	switch p.OutboundType {
 	case azure.UserDefinedRoutingOutboundType:
@@ -268,5 +375,39 @@ func validateAzureStack(p *azure.Platform, fldPath *field.Path) field.ErrorList
 	case azure.NatGatewayOutboundType:
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("outboundType"), p.OutboundType, "Azure Stack does not support NAT routing currently"))
 	}
+	if p.LoadBalancerSKU == azure.StandardLoadBalancerSKU {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("loadBalancerSKU"), p.LoadBalancerSKU, "Azure Stack only supports basic load balancers"))
+	}
+	return allErrs
+}
+
+// validateLoadBalancers checks that the additional load balancers have unique valid names,
+// which the cloud provider does not suffix with -internal itself, and that the load balancers
+// of the cluster are standard ones.
+func validateLoadBalancers(p *azure.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(p.LoadBalancers) == 0 {
+		return allErrs
+	}
+	if p.CloudName == azure.StackCloud || p.LoadBalancerSKU == azure.BasicLoadBalancerSKU {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("loadBalancers"), "additional load balancers are only supported with standard load balancers"))
+	}
+	names := map[string]struct{}{}
+	for i, lb := range p.LoadBalancers {
+		lbPath := fldPath.Child("loadBalancers").Index(i)
+		switch {
+		case lb.Name == "":
+			allErrs = append(allErrs, field.Required(lbPath.Child("name"), "must provide the name of the load balancer"))
+		case !loadBalancerNameRegex.MatchString(lb.Name):
+			allErrs = append(allErrs, field.Invalid(lbPath.Child("name"), lb.Name, "must be at most 80 characters long, can only contain alphanumerics, underscores, periods and hyphens, and must start with an alphanumeric and end with an alphanumeric or underscore"))
+		case strings.HasSuffix(lb.Name, "-internal"):
+			allErrs = append(allErrs, field.Invalid(lbPath.Child("name"), lb.Name, "must not end with -internal, which names the internal load balancer of each load balancer"))
+		default:
+			if _, ok := names[lb.Name]; ok {
+				allErrs = append(allErrs, field.Duplicate(lbPath.Child("name"), lb.Name))
+			}
+			names[lb.Name] = struct{}{}
+		}
+	}
 	return allErrs
 }
@@ -127,6 +127,141 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			expected: `^\[test-path\.networkResourceGroupName: Required value: must provide a network resource group when a virtual network is specified, test-path\.networkResourceGroupName: Required value: must provide a network resource group when supplying subnets\]$`,
 		},
+		{
+			name: "valid putVMSSVMBatchSize",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.PutVMSSVMBatchSize = 10
+				return p
+			}(),
+		},
+		{
+			name: "negative putVMSSVMBatchSize",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.PutVMSSVMBatchSize = -1
+				return p
+			}(),
+			expected: `^test-path\.putVMSSVMBatchSize: Invalid value: -1: must be a positive integer$`,
+		},
+		{
+			name: "valid resource prefix",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.ResourcePrefix = "byo-infra_1"
+				return p
+			}(),
+		},
+		{
+			name: "invalid resource prefix",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.ResourcePrefix = "-byo-infra."
+				return p
+			}(),
+			expected: `^test-path\.resourcePrefix: Invalid value: "-byo-infra\.": must be at most 63 characters long, can only contain alphanumerics, underscores, periods and hyphens, and must start with an alphanumeric and end with an alphanumeric or underscore$`,
+		},
+		{
+			name: "valid route table name",
+			platform: func() *azure.Platform {
+				p := validNetworkPlatform()
+				p.RouteTableName = "byo-routes_1"
+				return p
+			}(),
+		},
+		{
+			name: "invalid route table name",
+			platform: func() *azure.Platform {
+				p := validNetworkPlatform()
+				p.RouteTableName = "byo-routes."
+				return p
+			}(),
+			expected: `^test-path\.routeTableName: Invalid value: "byo-routes\.": must be at most 80 characters long, can only contain alphanumerics, underscores, periods and hyphens, and must start with an alphanumeric and end with an alphanumeric or underscore$`,
+		},
+		{
+			name: "route table name without virtual network",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.RouteTableName = "byo-routes"
+				return p
+			}(),
+			expected: `^test-path\.routeTableName: Forbidden: a route table name is only allowed when a virtual network is specified$`,
+		},
+		{
+			name: "valid service endpoints",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.ServiceEndpoints = []azure.ServiceEndpoint{{
+					Name: azure.ResourceManagerServiceEndpoint,
+					URL:  "https://management.privatelink.azure.com",
+				}, {
+					Name: azure.ActiveDirectoryServiceEndpoint,
+					URL:  "https://login.privatelink.microsoftonline.com/",
+				}}
+				return p
+			}(),
+		},
+		{
+			name: "unknown service endpoint",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.ServiceEndpoints = []azure.ServiceEndpoint{{Name: "Storage", URL: "https://blob.privatelink.azure.com"}}
+				return p
+			}(),
+			expected: `^test-path\.serviceEndpoints\[0\]\.name: Unsupported value: "Storage": supported values: "ResourceManager", "ActiveDirectory"$`,
+		},
+		{
+			name: "duplicate service endpoint",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.ServiceEndpoints = []azure.ServiceEndpoint{
+					{Name: azure.ResourceManagerServiceEndpoint, URL: "https://management.privatelink.azure.com"},
+					{Name: azure.ResourceManagerServiceEndpoint, URL: "https://arm.privatelink.azure.com"},
+				}
+				return p
+			}(),
+			expected: `^test-path\.serviceEndpoints\[1\]\.name: Invalid value: "ResourceManager": duplicate service endpoint not allowed for ResourceManager, service endpoint already defined at test-path\.serviceEndpoints\[0\]$`,
+		},
+		{
+			name: "http service endpoint",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.ServiceEndpoints = []azure.ServiceEndpoint{{Name: azure.ResourceManagerServiceEndpoint, URL: "http://management.privatelink.azure.com"}}
+				return p
+			}(),
+			expected: `^test-path\.serviceEndpoints\[0\]\.url: Invalid value: "http://management\.privatelink\.azure\.com": only https scheme is allowed$`,
+		},
+		{
+			name: "service endpoints on Azure Stack",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.CloudName = azure.StackCloud
+				p.ARMEndpoint = "https://management.local.azurestack.external"
+				p.ServiceEndpoints = []azure.ServiceEndpoint{{Name: azure.ResourceManagerServiceEndpoint, URL: "https://management.privatelink.azure.com"}}
+				return p
+			}(),
+			expected: `test-path\.serviceEndpoints: Forbidden: service endpoints are not supported on AzureStackCloud, the endpoints are set from armEndpoint`,
+		},
+		{
+			name: "http ARM endpoint on Azure Stack",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.CloudName = azure.StackCloud
+				p.ARMEndpoint = "http://management.local.azurestack.external"
+				return p
+			}(),
+			expected: `test-path\.armEndpoint: Invalid value: "http://management\.local\.azurestack\.external": only https scheme is allowed`,
+		},
+		{
+			name: "ARM endpoint without host on Azure Stack",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.CloudName = azure.StackCloud
+				p.ARMEndpoint = "https:///metadata"
+				return p
+			}(),
+			expected: `test-path\.armEndpoint: Invalid value: "https:///metadata": host cannot be empty`,
+		},
 		{
 			name: "missing cloud name",
 			platform: func() *azure.Platform {
@@ -172,6 +307,70 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			expected: `^test-path\.outboundType: Invalid value: "NatGateway": not supported in this feature set$`,
 		},
+		{
+			name: "basic load balancer SKU",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.LoadBalancerSKU = azure.BasicLoadBalancerSKU
+				return p
+			}(),
+		},
+		{
+			name: "invalid load balancer SKU",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.LoadBalancerSKU = "premium"
+				return p
+			}(),
+			expected: `^test-path\.loadBalancerSKU: Unsupported value: "premium": supported values: "basic", "standard"$`,
+		},
+		{
+			name: "standard load balancer SKU on Azure Stack",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.CloudName = azure.StackCloud
+				p.ARMEndpoint = "https://management.local.azurestack.external"
+				p.LoadBalancerSKU = azure.StandardLoadBalancerSKU
+				return p
+			}(),
+			expected: `test-path\.loadBalancerSKU: Invalid value: "standard": Azure Stack only supports basic load balancers`,
+		},
+		{
+			name: "additional load balancers",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.LoadBalancers = []azure.LoadBalancer{{Name: "lb-a"}, {Name: "lb-b", PrimaryVMSet: "set-b"}}
+				return p
+			}(),
+		},
+		{
+			name: "duplicate additional load balancer",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.LoadBalancers = []azure.LoadBalancer{{Name: "lb-a"}, {Name: "lb-a"}}
+				return p
+			}(),
+			expected: `^test-path\.loadBalancers\[1\]\.name: Duplicate value: "lb-a"$`,
+		},
+		{
+			name: "internal additional load balancer",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.LoadBalancers = []azure.LoadBalancer{{Name: "lb-internal"}}
+				return p
+			}(),
+			expected: `^test-path\.loadBalancers\[0\]\.name: Invalid value: "lb-internal": must not end with -internal`,
+		},
+		{
+			name: "additional load balancers with basic load balancers",
+			platform: func() *azure.Platform {
+				p := validPlatform()
+				p.LoadBalancerSKU = azure.BasicLoadBalancerSKU
+				p.LoadBalancers = []azure.LoadBalancer{{Name: "lb-a"}}
+				return p
+			}(),
+			expected: `^test-path\.loadBalancers: Forbidden: additional load balancers are only supported with standard load balancers$`,
+		},
 		{
 			name: "missing key vault name",
 			platform: func() *azure.Platform {
@@ -27,4 +27,15 @@ type Platform struct {
 	// +kubebuilder:validation:Enum="";External
 	// +optional
 	CloudControllerManager CloudControllerManager `json:"cloudControllerManager,omitempty"`
+
+	// CloudProviderName is the name of the external cloud provider, for example equinixmetal, read by its
+	// cloud controller manager from the cloud provider config. When set, a cloud provider config is generated
+	// for the external cloud provider, which requires cloudControllerManager to be External.
+	// +optional
+	CloudProviderName string `json:"cloudProviderName,omitempty"`
+
+	// CloudProviderConfig is the config of the external cloud provider, which is passed as is in the cloud
+	// provider config. It is only used with cloudProviderName.
+	// +optional
+	CloudProviderConfig string `json:"cloudProviderConfig,omitempty"`
 }
@@ -69,6 +69,49 @@ type Platform struct {
 	// +default="Disabled"
 	// +kubebuilder:validation:Enum="Enabled";"Disabled"
 	UserProvisionedDNS UserProvisionedDNS `json:"userProvisionedDNS,omitempty"`
+
+	// ServiceEndpoints list contains custom endpoints which will override the default
+	// public endpoints of GCP services.
+	// There must be only one ServiceEndpoint for a service.
+	// +optional
+	ServiceEndpoints []ServiceEndpoint `json:"serviceEndpoints,omitempty"`
+
+	// NodeInstancePrefix is the prefix of the names of the instances of the
+	// nodes, which the cloud provider matches the nodes with, for environments
+	// naming the instances after another scheme than the infrastructure ID.
+	// Default: the infrastructure ID of the cluster.
+	// +optional
+	NodeInstancePrefix string `json:"nodeInstancePrefix,omitempty"`
+}
+
+// ServiceEndpointName is the name of a GCP service whose endpoint can be overridden.
+type ServiceEndpointName string
+
+const (
+	// ComputeServiceEndpoint is the endpoint override for the Compute Engine API.
+	ComputeServiceEndpoint ServiceEndpointName = "compute"
+
+	// ContainerServiceEndpoint is the endpoint override for the Kubernetes Engine API.
+	ContainerServiceEndpoint ServiceEndpointName = "container"
+
+	// StorageServiceEndpoint is the endpoint override for the Cloud Storage API.
+	StorageServiceEndpoint ServiceEndpointName = "storage"
+)
+
+// ServiceEndpoint stores the configuration for services to
+// override existing defaults of GCP services.
+type ServiceEndpoint struct {
+	// Name is the name of the GCP service.
+	// This must be provided and cannot be empty.
+	// +kubebuilder:validation:Enum="compute";"container";"storage"
+	Name ServiceEndpointName `json:"name"`
+
+	// URL is fully qualified URI with scheme https, that overrides the default generated
+	// endpoint for a client.
+	// This must be provided and cannot be empty.
+	//
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url"`
 }
 
 // UserLabel is a label to apply to GCP resources created for the cluster.
@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
 	"sort"
 
@@ -74,6 +75,9 @@ var (
 	// userLabelValueRegex is for verifying that the label value contains only allowed characters.
 	userLabelValueRegex = regexp.MustCompile(`^[0-9a-z_-]{1,63}$`)
 
+	// nodeInstancePrefixRegex is for verifying that the node instance prefix is the start of a valid instance name.
+	nodeInstancePrefixRegex = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
 	// userLabelKeyPrefixRegex is for verifying that the label key does not contain restricted prefixes.
 	userLabelKeyPrefixRegex = regexp.MustCompile(`^(?i)(kubernetes\-io|openshift\-io)`)
 )
@@ -119,6 +123,44 @@ func ValidatePlatform(p *gcp.Platform, fldPath *field.Path, ic *types.InstallCon
 	// check if configured userLabels are valid.
 	allErrs = append(allErrs, validateUserLabels(p.UserLabels, fldPath.Child("userLabels"))...)
 
+	allErrs = append(allErrs, validateServiceEndpoints(p.ServiceEndpoints, fldPath.Child("serviceEndpoints"))...)
+
+	if p.NodeInstancePrefix != "" && !nodeInstancePrefixRegex.MatchString(p.NodeInstancePrefix) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeInstancePrefix"), p.NodeInstancePrefix, "must start with a lowercase letter, end with a lowercase letter or digit, only contain lowercase letters, digits and hyphens, and be at most 63 characters long"))
+	}
+
+	return allErrs
+}
+
+// validateServiceEndpoints checks that every service endpoint override names a known
+// service, is not duplicated, and is an https URL with a host.
+func validateServiceEndpoints(endpoints []gcp.ServiceEndpoint, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	validNames := []string{string(gcp.ComputeServiceEndpoint), string(gcp.ContainerServiceEndpoint), string(gcp.StorageServiceEndpoint)}
+	tracker := map[gcp.ServiceEndpointName]int{}
+	for idx, e := range endpoints {
+		fldp := fldPath.Index(idx)
+		switch e.Name {
+		case gcp.ComputeServiceEndpoint, gcp.ContainerServiceEndpoint, gcp.StorageServiceEndpoint:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldp.Child("name"), e.Name, validNames))
+		}
+		if eidx, ok := tracker[e.Name]; ok {
+			allErrs = append(allErrs, field.Invalid(fldp.Child("name"), e.Name, fmt.Sprintf("duplicate service endpoint not allowed for %s, service endpoint already defined at %s", e.Name, fldPath.Index(eidx))))
+		} else {
+			tracker[e.Name] = idx
+		}
+
+		u, err := url.Parse(e.URL)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldp.Child("url"), e.URL, err.Error()))
+		case u.Scheme != "https":
+			allErrs = append(allErrs, field.Invalid(fldp.Child("url"), e.URL, "only https scheme is allowed"))
+		case u.Hostname() == "":
+			allErrs = append(allErrs, field.Invalid(fldp.Child("url"), e.URL, "host cannot be empty"))
+		}
+	}
 	return allErrs
 }
 
@@ -50,6 +50,60 @@ func TestValidatePlatform(t *testing.T) {
 			},
 			valid: true,
 		},
+		{
+			name: "node instance prefix",
+			platform: &gcp.Platform{
+				Region:             "us-east1",
+				NodeInstancePrefix: "custom-nodes",
+			},
+			valid: true,
+		},
+		{
+			name: "invalid node instance prefix",
+			platform: &gcp.Platform{
+				Region:             "us-east1",
+				NodeInstancePrefix: "Custom_Nodes",
+			},
+			valid: false,
+		},
+		{
+			name: "valid service endpoints",
+			platform: &gcp.Platform{
+				Region: "us-east1",
+				ServiceEndpoints: []gcp.ServiceEndpoint{
+					{Name: gcp.ComputeServiceEndpoint, URL: "https://compute.example.com"},
+					{Name: gcp.ContainerServiceEndpoint, URL: "https://container.example.com"},
+				},
+			},
+			valid: true,
+		},
+		{
+			name: "duplicate service endpoints",
+			platform: &gcp.Platform{
+				Region: "us-east1",
+				ServiceEndpoints: []gcp.ServiceEndpoint{
+					{Name: gcp.ComputeServiceEndpoint, URL: "https://compute.example.com"},
+					{Name: gcp.ComputeServiceEndpoint, URL: "https://compute2.example.com"},
+				},
+			},
+			valid: false,
+		},
+		{
+			name: "unknown service endpoint",
+			platform: &gcp.Platform{
+				Region:           "us-east1",
+				ServiceEndpoints: []gcp.ServiceEndpoint{{Name: "dns", URL: "https://dns.example.com"}},
+			},
+			valid: false,
+		},
+		{
+			name: "insecure service endpoint",
+			platform: &gcp.Platform{
+				Region:           "us-east1",
+				ServiceEndpoints: []gcp.ServiceEndpoint{{Name: gcp.ComputeServiceEndpoint, URL: "http://compute.example.com"}},
+			},
+			valid: false,
+		},
 		{
 			name: "missing subnets",
 			platform: &gcp.Platform{
@@ -163,8 +163,36 @@ type Platform struct {
 	// There must only be one ServiceEndpoint for a service (no duplicates).
 	// +optional
 	ServiceEndpoints []configv1.IBMCloudServiceEndpoint `json:"serviceEndpoints,omitempty"`
+
+	// LoadBalancerProfile is the profile of the VPC load balancers created by the cloud
+	// provider for Services of type LoadBalancer.
+	// When unset, the standard application load balancer profile is used.
+	// +kubebuilder:validation:Enum="";application;network
+	// +optional
+	LoadBalancerProfile LoadBalancerProfile `json:"loadBalancerProfile,omitempty"`
+
+	// Satellite configures the install on an IBM Cloud Satellite location
+	// instead of an IBM Cloud VPC region.
+	// +optional
+	Satellite *Satellite `json:"satellite,omitempty"`
+}
+
+// Satellite stores the configuration of an IBM Cloud Satellite install.
+type Satellite struct {
+	// Location is the ID of the Satellite location which hosts the cluster.
+	Location string `json:"location"`
 }
 
+// LoadBalancerProfile is the profile of an IBM Cloud VPC load balancer.
+type LoadBalancerProfile string
+
+const (
+	// ApplicationLoadBalancerProfile is the profile of the VPC application load balancers.
+	ApplicationLoadBalancerProfile LoadBalancerProfile = "application"
+	// NetworkLoadBalancerProfile is the profile of the VPC network load balancers.
+	NetworkLoadBalancerProfile LoadBalancerProfile = "network"
+)
+
 // ClusterResourceGroupName returns the name of the resource group for the cluster.
 func (p *Platform) ClusterResourceGroupName(infraID string) string {
 	if len(p.ResourceGroupName) > 0 {
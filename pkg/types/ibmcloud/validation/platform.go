@@ -68,6 +68,16 @@ func ValidatePlatform(p *ibmcloud.Platform, fldPath *field.Path) field.ErrorList
 	if p.ServiceEndpoints != nil {
 		allErrs = append(allErrs, validateServiceEndpoints(p.ServiceEndpoints, fldPath.Child("serviceEndpoints"))...)
 	}
+
+	switch p.LoadBalancerProfile {
+	case "", ibmcloud.ApplicationLoadBalancerProfile, ibmcloud.NetworkLoadBalancerProfile:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("loadBalancerProfile"), p.LoadBalancerProfile, []string{string(ibmcloud.ApplicationLoadBalancerProfile), string(ibmcloud.NetworkLoadBalancerProfile)}))
+	}
+
+	if p.Satellite != nil && p.Satellite.Location == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("satellite", "location"), "location must be specified for Satellite installs"))
+	}
 	return allErrs
 }
 
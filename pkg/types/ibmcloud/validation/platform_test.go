@@ -62,6 +62,42 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			valid: true,
 		},
+		{
+			name: "valid load balancer profile",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.LoadBalancerProfile = ibmcloud.NetworkLoadBalancerProfile
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "invalid load balancer profile",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.LoadBalancerProfile = "gateway"
+				return p
+			}(),
+			valid: false,
+		},
+		{
+			name: "valid satellite location",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.Satellite = &ibmcloud.Satellite{Location: "c2b5gk6w0c5fd0p0s8ag"}
+				return p
+			}(),
+			valid: true,
+		},
+		{
+			name: "missing satellite location",
+			platform: func() *ibmcloud.Platform {
+				p := validMinimalPlatform()
+				p.Satellite = &ibmcloud.Satellite{}
+				return p
+			}(),
+			valid: false,
+		},
 		{
 			name: "valid vpc and subnets",
 			platform: func() *ibmcloud.Platform {
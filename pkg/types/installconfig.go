@@ -219,6 +219,15 @@ type InstallConfig struct {
 	// E.g. "featureGates": ["FeatureGate1=true", "FeatureGate2=false"].
 	// +optional
 	FeatureGates []string `json:"featureGates,omitempty"`
+
+	// CloudProviderConfigAdditions maps platform names to snippets merged into
+	// the cloud provider config generated for the platform. The keys of an INI
+	// snippet replace the ones of the same sections, and a JSON or YAML snippet
+	// is merged into the config field by field. The snippets take precedence
+	// over the generated config, and the overrides of the
+	// cloud-provider-config-overrides.yaml file over the snippets.
+	// +optional
+	CloudProviderConfigAdditions map[string]string `json:"cloudProviderConfigAdditions,omitempty"`
 }
 
 // ClusterDomain returns the DNS domain that all records for a cluster must belong to.
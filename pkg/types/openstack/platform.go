@@ -129,4 +129,80 @@ type Platform struct {
 	// LoadBalancer defines how the load balancer used by the cluster is configured.
 	// +optional
 	LoadBalancer *configv1.OpenStackPlatformLoadBalancer `json:"loadBalancer,omitempty"`
+
+	// ManageSecurityGroups makes the cloud provider create and manage the security groups
+	// of the load balancers it provisions for Services.
+	// Default: the security groups of the load balancers are not managed by the cloud provider.
+	// +optional
+	ManageSecurityGroups bool `json:"manageSecurityGroups,omitempty"`
+
+	// LoadBalancerCACertFile is the path to the CA certificate of the Octavia load balancer
+	// endpoint, used when it is not signed by the CA of the clouds.yaml cloud.
+	// +optional
+	LoadBalancerCACertFile string `json:"loadBalancerCACertFile,omitempty"`
+
+	// LoadBalancerClasses are the classes of load balancers which Services can select, for
+	// example to allocate their floating IPs from other external networks than externalNetwork.
+	// +optional
+	LoadBalancerClasses []LoadBalancerClass `json:"loadBalancerClasses,omitempty"`
+
+	// RegionName is the region of the clouds.yaml cloud in which the cluster is created, for
+	// clouds listing several regions. It takes precedence over the region_name of the cloud.
+	// Default: the region_name of the clouds.yaml cloud.
+	// +optional
+	RegionName string `json:"regionName,omitempty"`
+
+	// AdditionalRegions are the regions of the cloud, besides the region of the clouds.yaml
+	// cloud, in which nodes of the cluster run.
+	// When unset, the cloud provider only manages the nodes of the region of the cloud.
+	// +optional
+	AdditionalRegions []AdditionalRegion `json:"additionalRegions,omitempty"`
+
+	// MetadataSearchOrder is the order in which the cloud provider looks up the metadata of
+	// the instances, for example their Nova instance name used as the node name.
+	// Default: the cloud provider default, the config drive then the metadata service.
+	// +optional
+	MetadataSearchOrder []MetadataSource `json:"metadataSearchOrder,omitempty"`
+
+	// InternalNetworkName is the name of the network whose addresses are the internal
+	// addresses of the nodes, for nodes attached to several networks.
+	// Default: the cloud provider picks the internal addresses among all the networks.
+	// +optional
+	InternalNetworkName string `json:"internalNetworkName,omitempty"`
+}
+
+// MetadataSource is a source of the metadata of the instances.
+// +kubebuilder:validation:Enum=configDrive;metadataService
+type MetadataSource string
+
+const (
+	// ConfigDriveMetadataSource reads the metadata from the config drive of the instance.
+	ConfigDriveMetadataSource MetadataSource = "configDrive"
+
+	// MetadataServiceMetadataSource reads the metadata from the Nova metadata service.
+	MetadataServiceMetadataSource MetadataSource = "metadataService"
+)
+
+// LoadBalancerClass is a class of load balancers which Services can select.
+type LoadBalancerClass struct {
+	// Name is the name of the class, set in the loadbalancer.openstack.org/class annotation of
+	// the Services.
+	Name string `json:"name"`
+
+	// FloatingNetwork is the name of the external network the floating IPs of the load balancers
+	// of the class are allocated from.
+	// Default: the externalNetwork of the platform.
+	// +optional
+	FloatingNetwork string `json:"floatingNetwork,omitempty"`
+}
+
+// AdditionalRegion is a region of the cloud in which nodes of the cluster run.
+type AdditionalRegion struct {
+	// Name is the name of the region.
+	Name string `json:"name"`
+
+	// CACertFile is the path to the CA certificate of the endpoints of the region, used when
+	// they are not signed by the CA of the clouds.yaml cloud.
+	// +optional
+	CACertFile string `json:"caCertFile,omitempty"`
 }
@@ -32,6 +32,68 @@ func ValidatePlatform(p *openstack.Platform, n *types.Networking, fldPath *field
 		allErrs = append(allErrs, validateControlPlanePort(controlPlanePort, fldPath.Child("controlPlanePort"))...)
 	}
 
+	allErrs = append(allErrs, validateLoadBalancerClasses(p.LoadBalancerClasses, fldPath.Child("loadBalancerClasses"))...)
+	allErrs = append(allErrs, validateAdditionalRegions(p.AdditionalRegions, fldPath.Child("additionalRegions"))...)
+	allErrs = append(allErrs, validateMetadataSearchOrder(p.MetadataSearchOrder, fldPath.Child("metadataSearchOrder"))...)
+
+	return allErrs
+}
+
+// validateLoadBalancerClasses returns all the errors found when the load balancer classes are not valid.
+func validateLoadBalancerClasses(classes []openstack.LoadBalancerClass, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]struct{}, len(classes))
+	for i, class := range classes {
+		idxPath := fldPath.Index(i)
+		if class.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "a load balancer class name must be set"))
+		} else if _, ok := names[class.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), class.Name))
+		}
+		names[class.Name] = struct{}{}
+	}
+
+	return allErrs
+}
+
+// validateAdditionalRegions returns all the errors found when the additional regions are not valid.
+func validateAdditionalRegions(regions []openstack.AdditionalRegion, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := make(map[string]struct{}, len(regions))
+	for i, region := range regions {
+		idxPath := fldPath.Index(i)
+		if region.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "a region name must be set"))
+		} else if _, ok := names[region.Name]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), region.Name))
+		}
+		names[region.Name] = struct{}{}
+	}
+
+	return allErrs
+}
+
+// validateMetadataSearchOrder returns all the errors found when the metadata search order is not valid.
+func validateMetadataSearchOrder(sources []openstack.MetadataSource, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	seen := make(map[openstack.MetadataSource]struct{}, len(sources))
+	for i, source := range sources {
+		idxPath := fldPath.Index(i)
+		switch source {
+		case openstack.ConfigDriveMetadataSource, openstack.MetadataServiceMetadataSource:
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath, source, []string{string(openstack.ConfigDriveMetadataSource), string(openstack.MetadataServiceMetadataSource)}))
+			continue
+		}
+		if _, ok := seen[source]; ok {
+			allErrs = append(allErrs, field.Duplicate(idxPath, source))
+		}
+		seen[source] = struct{}{}
+	}
+
 	return allErrs
 }
 
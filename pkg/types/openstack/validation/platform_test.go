@@ -128,6 +128,108 @@ func TestValidatePlatform(t *testing.T) {
 			networking: validNetworking(),
 			valid:      true,
 		},
+		{
+			name: "valid load balancer classes",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.LoadBalancerClasses = []openstack.LoadBalancerClass{
+					{Name: "public", FloatingNetwork: "public-network"},
+					{Name: "default"},
+				}
+				return p
+			}(),
+			networking: validNetworking(),
+			valid:      true,
+		},
+		{
+			name: "duplicate load balancer classes",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.LoadBalancerClasses = []openstack.LoadBalancerClass{
+					{Name: "public", FloatingNetwork: "public-network"},
+					{Name: "public", FloatingNetwork: "other-network"},
+				}
+				return p
+			}(),
+			networking:    validNetworking(),
+			valid:         false,
+			expectedError: `test-path\.loadBalancerClasses\[1\]\.name: Duplicate value: "public"`,
+		},
+		{
+			name: "load balancer class without name",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.LoadBalancerClasses = []openstack.LoadBalancerClass{{FloatingNetwork: "public-network"}}
+				return p
+			}(),
+			networking:    validNetworking(),
+			valid:         false,
+			expectedError: `test-path\.loadBalancerClasses\[0\]\.name: Required value`,
+		},
+		{
+			name: "valid additional regions",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.AdditionalRegions = []openstack.AdditionalRegion{{Name: "region-b"}, {Name: "region-c", CACertFile: "/tmp/ca.pem"}}
+				return p
+			}(),
+			networking: validNetworking(),
+			valid:      true,
+		},
+		{
+			name: "duplicate additional regions",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.AdditionalRegions = []openstack.AdditionalRegion{{Name: "region-b"}, {Name: "region-b"}}
+				return p
+			}(),
+			networking:    validNetworking(),
+			valid:         false,
+			expectedError: `test-path\.additionalRegions\[1\]\.name: Duplicate value: "region-b"`,
+		},
+		{
+			name: "additional region without name",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.AdditionalRegions = []openstack.AdditionalRegion{{CACertFile: "/tmp/ca.pem"}}
+				return p
+			}(),
+			networking:    validNetworking(),
+			valid:         false,
+			expectedError: `test-path\.additionalRegions\[0\]\.name: Required value`,
+		},
+		{
+			name: "valid metadata search order",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.MetadataSearchOrder = []openstack.MetadataSource{openstack.MetadataServiceMetadataSource, openstack.ConfigDriveMetadataSource}
+				return p
+			}(),
+			networking: validNetworking(),
+			valid:      true,
+		},
+		{
+			name: "unsupported metadata source",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.MetadataSearchOrder = []openstack.MetadataSource{"ec2"}
+				return p
+			}(),
+			networking:    validNetworking(),
+			valid:         false,
+			expectedError: `test-path\.metadataSearchOrder\[0\]: Unsupported value: "ec2": supported values: "configDrive", "metadataService"`,
+		},
+		{
+			name: "duplicate metadata source",
+			platform: func() *openstack.Platform {
+				p := validPlatform()
+				p.MetadataSearchOrder = []openstack.MetadataSource{openstack.ConfigDriveMetadataSource, openstack.ConfigDriveMetadataSource}
+				return p
+			}(),
+			networking:    validNetworking(),
+			valid:         false,
+			expectedError: `test-path\.metadataSearchOrder\[1\]: Duplicate value: "configDrive"`,
+		},
 		{
 			name: "invalid subnet ID",
 			platform: func() *openstack.Platform {
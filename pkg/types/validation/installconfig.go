@@ -245,11 +245,31 @@ func ValidateInstallConfig(c *types.InstallConfig, usingAgentMethod bool) field.
 		}
 	}
 
+	allErrs = append(allErrs, validateCloudProviderConfigAdditions(c.CloudProviderConfigAdditions, field.NewPath("cloudProviderConfigAdditions"))...)
+
 	allErrs = append(allErrs, ValidateFeatureSet(c)...)
 
 	return allErrs
 }
 
+// validateCloudProviderConfigAdditions checks that the additions are keyed by
+// known platforms. Whether they merge into the generated config is only known
+// once it is generated.
+func validateCloudProviderConfigAdditions(additions map[string]string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	platforms := sets.New(types.PlatformNames...).Insert(types.HiddenPlatformNames...)
+	for _, platform := range sets.List(sets.KeySet(additions)) {
+		if !platforms.Has(platform) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Key(platform), platform, sets.List(platforms)))
+			continue
+		}
+		if strings.TrimSpace(additions[platform]) == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Key(platform), "the addition must not be empty"))
+		}
+	}
+	return allErrs
+}
+
 // ipAddressType indicates the address types provided for a given field
 type ipAddressType struct {
 	IPv4    bool
@@ -333,6 +353,12 @@ func validateNetworkingIPVersion(n *types.Networking, p *types.Platform) field.E
 		switch {
 		case p.Azure != nil && experimentalDualStackEnabled:
 			logrus.Warnf("Using experimental Azure dual-stack support")
+		case p.GCP != nil && experimentalDualStackEnabled:
+			logrus.Warnf("Using experimental GCP dual-stack support")
+			// The networks created by the installer only have IPv4 subnets.
+			if p.GCP.Network == "" || p.GCP.ControlPlaneSubnet == "" || p.GCP.ComputeSubnet == "" {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("platform", "gcp", "network"), p.GCP.Network, "dual-stack IPv4/IPv6 on GCP requires an existing network with dual-stack control plane and compute subnets"))
+			}
 		case p.BareMetal != nil:
 			// We now support ipv6-primary dual stack on baremetal
 			allowV6Primary = true
@@ -941,6 +967,24 @@ func validatePlatform(platform *types.Platform, usingAgentMethod bool, fldPath *
 			return nutanixvalidation.ValidatePlatform(platform.Nutanix, f, c)
 		})
 	}
+	if platform.External != nil {
+		validate(external.Name, platform.External, func(f *field.Path) field.ErrorList {
+			return validateExternalPlatform(platform.External, f)
+		})
+	}
+	return allErrs
+}
+
+// validateExternalPlatform checks that the cloud provider config of the external
+// platform is only set for an external cloud controller manager.
+func validateExternalPlatform(p *external.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if p.CloudProviderName != "" && p.CloudControllerManager != external.CloudControllerManagerTypeExternal {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cloudProviderName"), p.CloudProviderName, fmt.Sprintf("cloudControllerManager must be %s to use an external cloud provider", external.CloudControllerManagerTypeExternal)))
+	}
+	if p.CloudProviderConfig != "" && p.CloudProviderName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("cloudProviderName"), "the name of the external cloud provider must be set with its config"))
+	}
 	return allErrs
 }
 
@@ -64,7 +64,7 @@ func validAWSPlatform() *aws.Platform {
 func validAzureStackPlatform() *azure.Platform {
 	return &azure.Platform{
 		Region:                      "test-region",
-		ARMEndpoint:                 "http://test-endpoint.com",
+		ARMEndpoint:                 "https://test-endpoint.com",
 		BaseDomainResourceGroupName: "test-basedomain-rg",
 		CloudName:                   azure.StackCloud,
 		OutboundType:                "Loadbalancer",
@@ -2364,6 +2364,44 @@ func TestValidateInstallConfig(t *testing.T) {
 				return c
 			}(),
 		},
+		{
+			name: "valid external cloud provider",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform.AWS = nil
+				c.Platform.External = &external.Platform{
+					CloudControllerManager: external.CloudControllerManagerTypeExternal,
+					CloudProviderName:      "equinixmetal",
+					CloudProviderConfig:    "{}",
+				}
+				return c
+			}(),
+		},
+		{
+			name: "external cloud provider without external cloud controller manager",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform.AWS = nil
+				c.Platform.External = &external.Platform{
+					CloudProviderName: "equinixmetal",
+				}
+				return c
+			}(),
+			expectedError: `^platform\.external\.cloudProviderName: Invalid value: "equinixmetal": cloudControllerManager must be External to use an external cloud provider$`,
+		},
+		{
+			name: "external cloud provider config without name",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.Platform.AWS = nil
+				c.Platform.External = &external.Platform{
+					CloudControllerManager: external.CloudControllerManagerTypeExternal,
+					CloudProviderConfig:    "{}",
+				}
+				return c
+			}(),
+			expectedError: `^platform\.external\.cloudProviderName: Required value: the name of the external cloud provider must be set with its config$`,
+		},
 		{
 			name: "valid disabled CloudController configuration platform External 2",
 			installConfig: func() *types.InstallConfig {
@@ -2395,6 +2433,35 @@ func TestValidateInstallConfig(t *testing.T) {
 			}(),
 			expectedError: "disabling CloudControllerManager on External platform supported only with cloudControllerManager value none",
 		},
+		{
+			name: "valid cloud provider config additions",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.CloudProviderConfigAdditions = map[string]string{
+					aws.Name:   "[Global]\nDisableStrictZoneCheck = true\n",
+					azure.Name: `{"loadBalancerSku": "basic"}`,
+				}
+				return c
+			}(),
+		},
+		{
+			name: "cloud provider config additions of an unknown platform",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.CloudProviderConfigAdditions = map[string]string{"unknown": "[Global]\n"}
+				return c
+			}(),
+			expectedError: `^cloudProviderConfigAdditions\[unknown\]: Unsupported value: "unknown": supported values: .*"aws".*$`,
+		},
+		{
+			name: "empty cloud provider config addition",
+			installConfig: func() *types.InstallConfig {
+				c := validInstallConfig()
+				c.CloudProviderConfigAdditions = map[string]string{aws.Name: "\n"}
+				return c
+			}(),
+			expectedError: `^cloudProviderConfigAdditions\[aws\]: Required value: the addition must not be empty$`,
+		},
 		{
 			name: "Ingress can't be disabled",
 			installConfig: func() *types.InstallConfig {
@@ -2419,6 +2486,37 @@ func TestValidateInstallConfig(t *testing.T) {
 	}
 }
 
+func TestValidateGCPDualStack(t *testing.T) {
+	t.Setenv("OPENSHIFT_INSTALL_EXPERIMENTAL_DUAL_STACK", "true")
+	cases := []struct {
+		name          string
+		platform      *gcp.Platform
+		expectedError string
+	}{{
+		name: "existing network",
+		platform: func() *gcp.Platform {
+			p := validGCPPlatform()
+			p.Network = "test-network"
+			p.ControlPlaneSubnet = "test-master-subnet"
+			p.ComputeSubnet = "test-worker-subnet"
+			return p
+		}(),
+	}, {
+		name:          "installer-created network",
+		platform:      validGCPPlatform(),
+		expectedError: `platform\.gcp\.network: Invalid value: "": dual-stack IPv4/IPv6 on GCP requires an existing network with dual-stack control plane and compute subnets`,
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateNetworkingIPVersion(validDualStackNetworkingConfig(), &types.Platform{GCP: tc.platform})
+			if tc.expectedError == "" {
+				assert.Empty(t, errs)
+			} else {
+				assert.Regexp(t, tc.expectedError, errs.ToAggregate())
+			}
+		})
+	}
+}
 func Test_ensureIPv4IsFirstInDualStackSlice(t *testing.T) {
 	tests := []struct {
 		name    string
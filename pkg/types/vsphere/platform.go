@@ -136,6 +136,45 @@ type Platform struct {
 	LoadBalancer *configv1.VSpherePlatformLoadBalancer `json:"loadBalancer,omitempty"`
 	// Hosts defines network configurations to be applied by the installer. Hosts is available in TechPreview.
 	Hosts []*Host `json:"hosts,omitempty"`
+	// NodeNetwork is the name of the network providing the addresses of the nodes.
+	// It is required when a failure domain attaches the nodes to more than one network,
+	// and must then be one of the networks of every failure domain.
+	// +optional
+	NodeNetwork string `json:"nodeNetwork,omitempty"`
+	// RegionTagCategory is the name of the vCenter tag category of the tags naming the regions
+	// of the failure domains, read by the cloud provider to set the topology labels of the nodes.
+	// If empty, the openshift-region tag category is used.
+	// +kubebuilder:validation:MaxLength=80
+	// +optional
+	RegionTagCategory string `json:"regionTagCategory,omitempty"`
+	// ZoneTagCategory is the name of the vCenter tag category of the tags naming the zones
+	// of the failure domains, read by the cloud provider to set the topology labels of the nodes.
+	// If empty, the openshift-zone tag category is used.
+	// +kubebuilder:validation:MaxLength=80
+	// +optional
+	ZoneTagCategory string `json:"zoneTagCategory,omitempty"`
+}
+
+// RegionTagCategoryName returns the name of the tag category of the regions.
+func (p *Platform) RegionTagCategoryName() string {
+	if p.RegionTagCategory != "" {
+		return p.RegionTagCategory
+	}
+	return TagCategoryRegion
+}
+
+// ZoneTagCategoryName returns the name of the tag category of the zones.
+func (p *Platform) ZoneTagCategoryName() string {
+	if p.ZoneTagCategory != "" {
+		return p.ZoneTagCategory
+	}
+	return TagCategoryZone
+}
+
+// HasTopologyTagCategories returns whether the tag categories of the regions
+// or the zones are set explicitly.
+func (p *Platform) HasTopologyTagCategories() bool {
+	return p.RegionTagCategory != "" || p.ZoneTagCategory != ""
 }
 
 // FailureDomain holds the region and zone failure domain and
@@ -187,10 +226,22 @@ type Topology struct {
 	Networks []string `json:"networks,omitempty"`
 	// datastore is the name or inventory path of the datastore in which the
 	// virtual machine is created/located.
-	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:MinLength=1
+	// Required unless datastoreURL or datastoreCluster is set.
 	// +kubebuilder:validation:MaxLength=2048
 	Datastore string `json:"datastore"`
+	// datastoreURL is the URL of the datastore in which the virtual machine is
+	// created/located, of the form ds:///vmfs/volumes/<datastore>/, for the
+	// datastores referenced by URL rather than inventory path, e.g. NFS or vSAN
+	// datastores. It must not be set along with datastore.
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	DatastoreURL string `json:"datastoreURL,omitempty"`
+	// datastoreCluster is the inventory path of the datastore cluster, with
+	// Storage DRS enabled, in which the virtual machine is created/located.
+	// It is ignored by the cloud provider when datastore is also set.
+	// +kubebuilder:validation:MaxLength=2048
+	// +optional
+	DatastoreCluster string `json:"datastoreCluster,omitempty"`
 	// resourcePool is the absolute path of the resource pool where virtual machines will be
 	// created. The absolute path is of the form /<datacenter>/host/<cluster>/Resources/<resourcepool>.
 	// +kubebuilder:validation:MinLength=1
@@ -244,6 +295,12 @@ type VCenter struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinItems=1
 	Datacenters []string `json:"datacenters"`
+	// Thumbprint is the SHA-1 fingerprint of the vCenter TLS certificate, as colon-separated
+	// hex pairs. When set, the cloud provider verifies the vCenter certificate against it.
+	// When every vCenter has a thumbprint, certificate verification is no longer skipped.
+	// +kubebuilder:validation:Pattern=`^([0-9A-Fa-f]{2}:){19}[0-9A-Fa-f]{2}$`
+	// +optional
+	Thumbprint string `json:"thumbprint,omitempty"`
 }
 
 // Host defines host VMs to generate as part of the installation.
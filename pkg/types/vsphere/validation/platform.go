@@ -47,6 +47,10 @@ func ValidatePlatform(p *vsphere.Platform, agentBasedInstallation bool, fldPath
 			return append(allErrs, field.Required(fldPath.Child("failureDomains"), "must be defined"))
 		}
 		allErrs = append(allErrs, validateFailureDomains(p, fldPath.Child("failureDomains"), isLegacyUpi)...)
+		allErrs = append(allErrs, validateNodeNetwork(p, fldPath.Child("nodeNetwork"))...)
+		if p.RegionTagCategoryName() == p.ZoneTagCategoryName() {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("zoneTagCategory"), p.ZoneTagCategoryName(), "the regions and zones must use different tag categories"))
+		}
 
 		// Validate hosts if configured for static IP
 		if p.Hosts != nil {
@@ -82,6 +86,12 @@ func ValidatePlatform(p *vsphere.Platform, agentBasedInstallation bool, fldPath
 	return allErrs
 }
 
+var thumbprintRegexp = regexp.MustCompile(`^([0-9A-Fa-f]{2}:){19}[0-9A-Fa-f]{2}$`)
+
+// datastoreURLRegexp matches the URLs of the datastores, e.g.
+// ds:///vmfs/volumes/vsan:52c6a2b1c3d4e5f6-0123456789abcdef/.
+var datastoreURLRegexp = regexp.MustCompile(`^ds:///vmfs/volumes/[^/]+/?$`)
+
 func validateVCenters(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -102,6 +112,9 @@ func validateVCenters(p *vsphere.Platform, fldPath *field.Path) field.ErrorList
 		if len(vCenter.Datacenters) == 0 {
 			allErrs = append(allErrs, field.Required(fldPath.Index(index).Child("datacenters"), "must specify at least one datacenter"))
 		}
+		if vCenter.Thumbprint != "" && !thumbprintRegexp.MatchString(vCenter.Thumbprint) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(index).Child("thumbprint"), vCenter.Thumbprint, "must be a SHA-1 fingerprint of 20 colon-separated hex pairs"))
+		}
 	}
 	return allErrs
 }
@@ -148,8 +161,29 @@ func validateFailureDomains(p *vsphere.Platform, fldPath *field.Path, isLegacyUp
 		if len(failureDomain.Topology.Datacenter) == 0 {
 			allErrs = append(allErrs, field.Required(topologyFld.Child("datacenter"), "must specify a datacenter"))
 		}
+		if len(failureDomain.Topology.DatastoreCluster) != 0 {
+			datastoreCluster := failureDomain.Topology.DatastoreCluster
+			if !regexp.MustCompile(`^/(.*?)/datastore/(.*?)$`).MatchString(datastoreCluster) {
+				return append(allErrs, field.Invalid(topologyFld.Child("datastoreCluster"), datastoreCluster, "full path of datastore cluster must be provided in format /<datacenter>/datastore/<datastore cluster>"))
+			}
+			if !strings.Contains(datastoreCluster, failureDomain.Topology.Datacenter) {
+				return append(allErrs, field.Invalid(topologyFld.Child("datastoreCluster"), datastoreCluster, "the datastore cluster defined does not exist in the correct datacenter"))
+			}
+			p.FailureDomains[index].Topology.DatastoreCluster = filepath.Clean(datastoreCluster)
+		}
+		if len(failureDomain.Topology.DatastoreURL) != 0 {
+			datastoreURL := failureDomain.Topology.DatastoreURL
+			if len(failureDomain.Topology.Datastore) != 0 {
+				return append(allErrs, field.Forbidden(topologyFld.Child("datastoreURL"), "datastore and datastoreURL are mutually exclusive"))
+			}
+			if !datastoreURLRegexp.MatchString(datastoreURL) {
+				return append(allErrs, field.Invalid(topologyFld.Child("datastoreURL"), datastoreURL, "datastore URL must be provided in format ds:///vmfs/volumes/<datastore>/"))
+			}
+		}
 		if len(failureDomain.Topology.Datastore) == 0 {
-			allErrs = append(allErrs, field.Required(topologyFld.Child("datastore"), "must specify a datastore"))
+			if len(failureDomain.Topology.DatastoreURL) == 0 && len(failureDomain.Topology.DatastoreCluster) == 0 {
+				allErrs = append(allErrs, field.Required(topologyFld.Child("datastore"), "must specify a datastore, a datastoreURL or a datastoreCluster"))
+			}
 		} else {
 			datastore := failureDomain.Topology.Datastore
 
@@ -245,6 +279,26 @@ func validateFailureDomains(p *vsphere.Platform, fldPath *field.Path, isLegacyUp
 }
 
 // validateDiskType checks that the specified diskType is valid.
+// validateNodeNetwork checks that the network providing the node addresses is
+// set when a failure domain has several networks, and that it is one of the
+// networks of every failure domain.
+func validateNodeNetwork(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for _, failureDomain := range p.FailureDomains {
+		networks := failureDomain.Topology.Networks
+		if p.NodeNetwork == "" {
+			if len(networks) > 1 {
+				allErrs = append(allErrs, field.Required(fldPath, fmt.Sprintf("must specify the network providing the node addresses since failure domain %s has multiple networks", failureDomain.Name)))
+			}
+			continue
+		}
+		if len(networks) > 0 && !slices.Contains(networks, p.NodeNetwork) {
+			allErrs = append(allErrs, field.Invalid(fldPath, p.NodeNetwork, fmt.Sprintf("must be one of the networks of failure domain %s", failureDomain.Name)))
+		}
+	}
+	return allErrs
+}
+
 func validateDiskType(p *vsphere.Platform, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
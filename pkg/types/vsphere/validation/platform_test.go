@@ -196,6 +196,53 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			expectedError: `^test-path\.diskType: Invalid value: "invalidDiskType": diskType must be one of \[eagerZeroedThick thick thin\]$`,
 		},
+		{
+			name: "Multiple networks with a node network",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				for i := range p.FailureDomains {
+					p.FailureDomains[i].Topology.Networks = append(p.FailureDomains[i].Topology.Networks, "test-portgroup-2")
+				}
+				p.NodeNetwork = "test-portgroup-2"
+				return p
+			}(),
+		},
+		{
+			name: "Multiple networks without a node network",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.Networks = append(p.FailureDomains[0].Topology.Networks, "test-portgroup-2")
+				return p
+			}(),
+			expectedError: `^test-path\.nodeNetwork: Required value: must specify the network providing the node addresses since failure domain test-east-1a has multiple networks$`,
+		},
+		{
+			name: "Node network missing from a failure domain",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.NodeNetwork = "test-portgroup-2"
+				return p
+			}(),
+			expectedError: `test-path\.nodeNetwork: Invalid value: "test-portgroup-2": must be one of the networks of failure domain`,
+		},
+		{
+			name: "Custom tag categories",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.RegionTagCategory = "k8s-region"
+				p.ZoneTagCategory = "k8s-zone"
+				return p
+			}(),
+		},
+		{
+			name: "Same tag category for regions and zones",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.ZoneTagCategory = vsphere.TagCategoryRegion
+				return p
+			}(),
+			expectedError: `^test-path\.zoneTagCategory: Invalid value: "openshift-region": the regions and zones must use different tag categories$`,
+		},
 		{
 			name: "Additional tag IDs provided",
 			platform: func() *vsphere.Platform {
@@ -293,6 +340,23 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			expectedError: `^test-path\.vcenters\[0].password: Required value: must specify the password$`,
 		},
+		{
+			name: "Multi-zone vCenter thumbprint",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.VCenters[0].Thumbprint = "AB:CD:EF:01:23:45:67:89:AB:CD:EF:01:23:45:67:89:AB:CD:EF:01"
+				return p
+			}(),
+		},
+		{
+			name: "Multi-zone invalid vCenter thumbprint",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.VCenters[0].Thumbprint = "AB:CD:EF"
+				return p
+			}(),
+			expectedError: `^test-path\.vcenters\[0].thumbprint: Invalid value: "AB:CD:EF": must be a SHA-1 fingerprint of 20 colon-separated hex pairs$`,
+		},
 		{
 			name: "Multi-zone missing datacenter",
 			platform: func() *vsphere.Platform {
@@ -302,6 +366,62 @@ func TestValidatePlatform(t *testing.T) {
 			}(),
 			expectedError: `^test-path\.vcenters\[0].datacenters: Required value: must specify at least one datacenter$`,
 		},
+		{
+			name: "Multi-zone platform datastore cluster",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.Datastore = ""
+				p.FailureDomains[0].Topology.DatastoreCluster = "/test-datacenter/datastore/test-datastore-cluster"
+				return p
+			}(),
+		},
+		{
+			name: "Multi-zone platform datastore cluster relative path",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.Datastore = ""
+				p.FailureDomains[0].Topology.DatastoreCluster = "test-datastore-cluster"
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\.topology\.datastoreCluster: Invalid value: "test-datastore-cluster": full path of datastore cluster must be provided in format /<datacenter>/datastore/<datastore cluster>$`,
+		},
+		{
+			name: "Multi-zone platform missing datastore and datastore cluster",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.Datastore = ""
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\.topology\.datastore: Required value: must specify a datastore, a datastoreURL or a datastoreCluster$`,
+		},
+		{
+			name: "Multi-zone platform datastore URL",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.Datastore = ""
+				p.FailureDomains[0].Topology.DatastoreURL = "ds:///vmfs/volumes/vsan:52c6a2b1c3d4e5f6-0123456789abcdef/"
+				return p
+			}(),
+		},
+		{
+			name: "Multi-zone platform invalid datastore URL",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.Datastore = ""
+				p.FailureDomains[0].Topology.DatastoreURL = "https://test-vcenter/folder/test-datastore"
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\.topology\.datastoreURL: Invalid value: "https://test-vcenter/folder/test-datastore": datastore URL must be provided in format ds:///vmfs/volumes/<datastore>/$`,
+		},
+		{
+			name: "Multi-zone platform datastore and datastore URL",
+			platform: func() *vsphere.Platform {
+				p := validPlatform()
+				p.FailureDomains[0].Topology.DatastoreURL = "ds:///vmfs/volumes/test-datastore-uuid/"
+				return p
+			}(),
+			expectedError: `^test-path\.failureDomains\.topology\.datastoreURL: Forbidden: datastore and datastoreURL are mutually exclusive$`,
+		},
 		{
 			name: "Multi-zone platform wrong vCenter name in failureDomain zone",
 			platform: func() *vsphere.Platform {